@@ -0,0 +1,68 @@
+package dynamoql
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// ErrUnprocessedItems Amazon DynamoDB did not process every item of a batch operation after exhausting retries.
+var ErrUnprocessedItems = errors.New("dynamoql: Unprocessed batch items")
+
+const (
+	// DefaultBatchRetries default number of consecutive retries of unprocessed items without any progress.
+	DefaultBatchRetries = 8
+	// DefaultBatchBaseDelay default base delay of the exponential backoff used to retry unprocessed items.
+	DefaultBatchBaseDelay = 50 * time.Millisecond
+	// DefaultBatchMaxDelay default maximum delay of the exponential backoff used to retry unprocessed items.
+	DefaultBatchMaxDelay = 5 * time.Second
+)
+
+// batchRetry exponential backoff with full jitter used by batch operations to retry unprocessed items as
+// recommended by Amazon DynamoDB.
+type batchRetry struct {
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+func newBatchRetry() batchRetry {
+	return batchRetry{
+		maxRetries: DefaultBatchRetries,
+		baseDelay:  DefaultBatchBaseDelay,
+		maxDelay:   DefaultBatchMaxDelay,
+	}
+}
+
+// delay calculates a random delay between zero and the exponential backoff of the given attempt (starting at
+// zero), capped by maxDelay.
+func (r batchRetry) delay(attempt int) time.Duration {
+	d := r.maxDelay
+	if attempt < 32 {
+		if exp := r.baseDelay << attempt; exp >= 0 && exp < d {
+			d = exp
+		}
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// wait blocks the current goroutine for the delay of the given attempt. Returns ctx error if ctx is done before
+// the delay elapses.
+func (r batchRetry) wait(ctx context.Context, attempt int) error {
+	d := r.delay(attempt)
+	if d == 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,465 @@
+package dynamoql
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// BatchGetMaxKeys maximum number of keys accepted by a single BatchGetItem API request.
+const BatchGetMaxKeys = 100
+
+// BatchGetBuilder crafts Amazon DynamoDB batch get statements ready to be used by BatchGetItem API.
+//
+// Accepts any number of keys from one or more tables. Keys are split into requests of at most BatchGetMaxKeys
+// keys and unprocessed keys are retried using exponential backoff with jitter. Duplicated keys are requested once.
+//
+// e.g. dynamoql.BatchGet("Graph", student.GetKeys(), classroom.GetKeys()).Concurrency(4)
+type BatchGetBuilder struct {
+	keys          []batchGetKey
+	tables        map[string]*batchGetTable
+	concurrency   int
+	retry         batchRetry
+	returnMetrics types.ReturnConsumedCapacity
+}
+
+type batchGetKey struct {
+	table string
+	key   map[string]types.AttributeValue
+}
+
+type batchGetTable struct {
+	projectedFields []string
+	consistentRead  bool
+}
+
+// BatchGetOutput items retrieved by a BatchGetBuilder.
+type BatchGetOutput struct {
+	// Items retrieved items in the same order their keys were added to the BatchGetBuilder. Items not found are
+	// nil.
+	Items []map[string]types.AttributeValue
+	// ConsumedCapacity consumed capacity of every BatchGetItem API request, only if Metrics were set.
+	ConsumedCapacity []types.ConsumedCapacity
+}
+
+// BatchGetError keys which could not be retrieved by a BatchGetBuilder.
+type BatchGetError struct {
+	// UnprocessedKeys item primary keys left unprocessed, by table name.
+	UnprocessedKeys map[string][]map[string]types.AttributeValue
+	Err             error
+}
+
+var _ error = BatchGetError{}
+
+func (e BatchGetError) Error() string {
+	return "dynamoql: Batch get failed: " + e.Err.Error()
+}
+
+// Unwrap retrieves the underlying error.
+func (e BatchGetError) Unwrap() error {
+	return e.Err
+}
+
+// NewBatchGetBuilder builds a BatchGetBuilder instance.
+func NewBatchGetBuilder() *BatchGetBuilder {
+	return &BatchGetBuilder{
+		tables:        make(map[string]*batchGetTable),
+		concurrency:   1,
+		retry:         newBatchRetry(),
+		returnMetrics: types.ReturnConsumedCapacityNone,
+	}
+}
+
+// BatchGet builds a new BatchGetBuilder instance and adds the given keys from table.
+func BatchGet(table string, keys ...map[string]types.AttributeValue) *BatchGetBuilder {
+	return NewBatchGetBuilder().Keys(table, keys...)
+}
+
+func (b *BatchGetBuilder) table(name string) *batchGetTable {
+	t, ok := b.tables[name]
+	if !ok {
+		t = &batchGetTable{}
+		b.tables[name] = t
+	}
+	return t
+}
+
+// Keys adds the given item primary keys from table. Items are returned in the same order keys were added.
+func (b *BatchGetBuilder) Keys(table string, keys ...map[string]types.AttributeValue) *BatchGetBuilder {
+	b.table(table)
+	for _, k := range keys {
+		b.keys = append(b.keys, batchGetKey{
+			table: table,
+			key:   k,
+		})
+	}
+	return b
+}
+
+// Select sets the attributes to retrieve from items of table. Primary key attributes are always retrieved as they
+// are required to order items.
+func (b *BatchGetBuilder) Select(table string, projectedFields ...string) *BatchGetBuilder {
+	b.table(table).projectedFields = projectedFields
+	return b
+}
+
+// StrongConsistency sets strongly consistent reads for items of table.
+func (b *BatchGetBuilder) StrongConsistency(table string) *BatchGetBuilder {
+	b.table(table).consistentRead = true
+	return b
+}
+
+// Concurrency sets the maximum number of BatchGetItem API requests running concurrently. Defaults to 1.
+func (b *BatchGetBuilder) Concurrency(n int) *BatchGetBuilder {
+	b.concurrency = n
+	return b
+}
+
+// Retries sets the maximum number of consecutive retries of unprocessed keys without any progress. Defaults to
+// DefaultBatchRetries.
+func (b *BatchGetBuilder) Retries(n int) *BatchGetBuilder {
+	b.retry.maxRetries = n
+	return b
+}
+
+// Backoff sets base and maximum delays of the exponential backoff used to retry unprocessed keys. Defaults to
+// DefaultBatchBaseDelay and DefaultBatchMaxDelay.
+func (b *BatchGetBuilder) Backoff(base, max time.Duration) *BatchGetBuilder {
+	b.retry.baseDelay = base
+	b.retry.maxDelay = max
+	return b
+}
+
+// Metrics sets the desired metric data from consumed capacity outputs.
+func (b *BatchGetBuilder) Metrics(v types.ReturnConsumedCapacity) *BatchGetBuilder {
+	b.returnMetrics = v
+	return b
+}
+
+// Validate verifies the current BatchGetBuilder instance values are accepted by BatchGetItem API, returning a
+// ValidationError if not.
+func (b *BatchGetBuilder) Validate() error {
+	if len(b.keys) == 0 {
+		return newValidationError(ErrMissingKeys, "", "at least one item primary key is required (Keys)")
+	} else if b.concurrency <= 0 {
+		return newValidationError(ErrInvalidOption, "", "concurrency must be greater than zero")
+	} else if b.retry.maxRetries < 0 || b.retry.baseDelay < 0 || b.retry.maxDelay < 0 {
+		return newValidationError(ErrInvalidOption, "", "retries and backoff delays must not be negative")
+	}
+	tableKeys := make(map[string][]string, len(b.tables))
+	for _, k := range b.keys {
+		if k.table == "" {
+			return newValidationError(ErrMissingTable, "", "table name is required (Keys)")
+		} else if len(k.key) == 0 {
+			return newValidationError(ErrMissingKeys, "", "item primary key of table "+k.table+" must not be empty")
+		}
+		names := keyAttributeNames(k.key)
+		for _, name := range names {
+			if keyValueIdentity(k.key[name]) == "" {
+				return newValidationError(ErrMissingKeys, name,
+					"primary key attributes must be either string, number or binary")
+			}
+		}
+		if prev, ok := tableKeys[k.table]; !ok {
+			tableKeys[k.table] = names
+		} else if strings.Join(prev, ",") != strings.Join(names, ",") {
+			return newValidationError(ErrMissingKeys, "",
+				"every item primary key of table "+k.table+" must have the same attributes")
+		}
+	}
+	return nil
+}
+
+// keyAttributeNames retrieves the sorted attribute names of the given primary key.
+func keyAttributeNames(key map[string]types.AttributeValue) []string {
+	names := make([]string, 0, len(key))
+	for k := range key {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keyValueIdentity encodes the given primary key attribute value. Numbers are normalized as Amazon DynamoDB does
+// (e.g. 1.50 => 3/2). Returns an empty string if v is not a valid primary key attribute.
+func keyValueIdentity(v types.AttributeValue) string {
+	switch attr := v.(type) {
+	case *types.AttributeValueMemberS:
+		return "S" + attr.Value
+	case *types.AttributeValueMemberN:
+		if r, ok := new(big.Rat).SetString(attr.Value); ok {
+			return "N" + r.String()
+		}
+		return "N" + attr.Value
+	case *types.AttributeValueMemberB:
+		return "B" + string(attr.Value)
+	default:
+		return ""
+	}
+}
+
+// keyIdentity encodes table and the attributes of the given item named after names into a single string which
+// identifies the item.
+func keyIdentity(table string, names []string, item map[string]types.AttributeValue) string {
+	buf := strings.Builder{}
+	buf.WriteString(strconv.Itoa(len(table)))
+	buf.WriteByte(':')
+	buf.WriteString(table)
+	for _, name := range names {
+		v := keyValueIdentity(item[name])
+		buf.WriteString(strconv.Itoa(len(name)))
+		buf.WriteByte(':')
+		buf.WriteString(name)
+		buf.WriteString(strconv.Itoa(len(v)))
+		buf.WriteByte(':')
+		buf.WriteString(v)
+	}
+	return buf.String()
+}
+
+// batchGetPlan unique keys of a BatchGetBuilder split into BatchGetItem API requests.
+type batchGetPlan struct {
+	inputs    []dynamodb.BatchGetItemInput
+	names     map[string][]string
+	positions map[string][]int
+}
+
+func (b *BatchGetBuilder) newPlan() (batchGetPlan, error) {
+	if err := b.Validate(); err != nil {
+		return batchGetPlan{}, err
+	}
+	plan := batchGetPlan{
+		inputs:    make([]dynamodb.BatchGetItemInput, 0, (len(b.keys)+BatchGetMaxKeys-1)/BatchGetMaxKeys),
+		names:     make(map[string][]string, len(b.tables)),
+		positions: make(map[string][]int, len(b.keys)),
+	}
+	var chunk map[string][]map[string]types.AttributeValue
+	chunkLen := 0
+	for i, k := range b.keys {
+		names, ok := plan.names[k.table]
+		if !ok {
+			names = keyAttributeNames(k.key)
+			plan.names[k.table] = names
+		}
+		id := keyIdentity(k.table, names, k.key)
+		positions, ok := plan.positions[id]
+		plan.positions[id] = append(positions, i)
+		if ok {
+			continue
+		}
+		if chunk == nil {
+			chunk = make(map[string][]map[string]types.AttributeValue)
+		}
+		chunk[k.table] = append(chunk[k.table], k.key)
+		chunkLen++
+		if chunkLen == BatchGetMaxKeys {
+			plan.inputs = append(plan.inputs, b.newInput(chunk, plan.names))
+			chunk, chunkLen = nil, 0
+		}
+	}
+	if chunkLen > 0 {
+		plan.inputs = append(plan.inputs, b.newInput(chunk, plan.names))
+	}
+	return plan, nil
+}
+
+func (b *BatchGetBuilder) newInput(chunk map[string][]map[string]types.AttributeValue,
+	names map[string][]string) dynamodb.BatchGetItemInput {
+	requests := make(map[string]types.KeysAndAttributes, len(chunk))
+	for table, keys := range chunk {
+		req := types.KeysAndAttributes{
+			Keys: keys,
+		}
+		opts := b.tables[table]
+		if opts.consistentRead {
+			req.ConsistentRead = &opts.consistentRead
+		}
+		if len(opts.projectedFields) > 0 {
+			a := newPlaceholderAllocator()
+			req.ProjectionExpression = buildProjectionExpression(a,
+				appendMissingFields(opts.projectedFields, names[table]))
+			req.ExpressionAttributeNames = a.attributeNames()
+		}
+		requests[table] = req
+	}
+	return dynamodb.BatchGetItemInput{
+		RequestItems:           requests,
+		ReturnConsumedCapacity: b.returnMetrics,
+	}
+}
+
+// appendMissingFields appends every field not contained by dst into a copy of dst.
+func appendMissingFields(dst, fields []string) []string {
+	buf := make([]string, len(dst), len(dst)+len(fields))
+	copy(buf, dst)
+	for _, field := range fields {
+		found := false
+		for i := range dst {
+			if dst[i] == field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			buf = append(buf, field)
+		}
+	}
+	return buf
+}
+
+// NewBatchGetInputs builds the dynamodb.BatchGetItemInput(s) required to retrieve every item from the current
+// BatchGetBuilder instance. Each input holds at most BatchGetMaxKeys keys.
+//
+// Returns a ValidationError if BatchGetBuilder values are not accepted by BatchGetItem API.
+func NewBatchGetInputs(b *BatchGetBuilder) ([]dynamodb.BatchGetItemInput, error) {
+	plan, err := b.newPlan()
+	if err != nil {
+		return nil, err
+	}
+	return plan.inputs, nil
+}
+
+// batchGetFunc executes a BatchGetItem API operation.
+type batchGetFunc func(context.Context, *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error)
+
+// ExecBatchGet executes as many BatchGetItem API operations as required to retrieve every item, running at most
+// Concurrency operations at the same time. Unprocessed keys are retried using exponential backoff with jitter.
+//
+// Returns a ValidationError if BatchGetBuilder values are not accepted by BatchGetItem API and a BatchGetError
+// (ErrUnprocessedItems) holding unprocessed keys if keys remain unprocessed after exhausting Retries. Items retrieved
+// before a failure are returned along with the error.
+func (b *BatchGetBuilder) ExecBatchGet(ctx context.Context, c *dynamodb.Client) (BatchGetOutput, error) {
+	return b.execBatchGet(ctx, func(ctx context.Context, in *dynamodb.BatchGetItemInput) (
+		*dynamodb.BatchGetItemOutput, error) {
+		return c.BatchGetItem(ctx, in)
+	})
+}
+
+func (b *BatchGetBuilder) execBatchGet(ctx context.Context, fn batchGetFunc) (BatchGetOutput, error) {
+	plan, err := b.newPlan()
+	if err != nil {
+		return BatchGetOutput{}, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	out := BatchGetOutput{
+		Items: make([]map[string]types.AttributeValue, len(b.keys)),
+	}
+	mu := sync.Mutex{}
+	var errExec error
+	var unprocessed map[string][]map[string]types.AttributeValue
+	collect := func(res *dynamodb.BatchGetItemOutput) {
+		for table, items := range res.Responses {
+			for _, item := range items {
+				// positions are written by a single request as keys are unique
+				for _, pos := range plan.positions[keyIdentity(table, plan.names[table], item)] {
+					out.Items[pos] = item
+				}
+			}
+		}
+		if len(res.ConsumedCapacity) > 0 {
+			mu.Lock()
+			out.ConsumedCapacity = append(out.ConsumedCapacity, res.ConsumedCapacity...)
+			mu.Unlock()
+		}
+	}
+
+	workers := b.concurrency
+	if workers > len(plan.inputs) {
+		workers = len(plan.inputs)
+	}
+	queue := make(chan int)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for n := range queue {
+				keys, err := b.execInput(ctx, fn, plan.inputs[n], collect)
+				if errors.Is(err, ErrUnprocessedItems) {
+					// remaining requests may still succeed
+					mu.Lock()
+					if unprocessed == nil {
+						unprocessed = make(map[string][]map[string]types.AttributeValue, len(keys))
+					}
+					for table, req := range keys {
+						unprocessed[table] = append(unprocessed[table], req.Keys...)
+					}
+					mu.Unlock()
+				} else if err != nil {
+					mu.Lock()
+					if errExec == nil {
+						errExec = err
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+dispatch:
+	for i := range plan.inputs {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case queue <- i:
+		}
+	}
+	close(queue)
+	wg.Wait()
+	if errExec != nil {
+		return out, errExec
+	} else if err = ctx.Err(); err != nil {
+		return out, err
+	} else if unprocessed != nil {
+		return out, BatchGetError{
+			UnprocessedKeys: unprocessed,
+			Err:             ErrUnprocessedItems,
+		}
+	}
+	return out, nil
+}
+
+// execInput executes the given BatchGetItem API operation, retrying unprocessed keys until every key is processed.
+//
+// Returns keys left unprocessed along with ErrUnprocessedItems after exhausting retries.
+func (b *BatchGetBuilder) execInput(ctx context.Context, fn batchGetFunc, in dynamodb.BatchGetItemInput,
+	collect func(*dynamodb.BatchGetItemOutput)) (map[string]types.KeysAndAttributes, error) {
+	attempt := 0
+	for {
+		out, err := fn(ctx, &in)
+		if err != nil {
+			return nil, err
+		}
+		collect(out)
+		if len(out.UnprocessedKeys) == 0 {
+			return nil, nil
+		} else if countBatchGetItems(out.Responses) > 0 {
+			// retries are only exhausted by consecutive operations without progress
+			attempt = 0
+		}
+		if attempt >= b.retry.maxRetries {
+			return out.UnprocessedKeys, ErrUnprocessedItems
+		} else if err = b.retry.wait(ctx, attempt); err != nil {
+			return nil, err
+		}
+		attempt++
+		in.RequestItems = out.UnprocessedKeys
+	}
+}
+
+func countBatchGetItems(responses map[string][]map[string]types.AttributeValue) int {
+	n := 0
+	for _, items := range responses {
+		n += len(items)
+	}
+	return n
+}
@@ -0,0 +1,268 @@
+package dynamoql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchTestKey(id int) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: "STUDENT#" + strconv.Itoa(id)},
+		"SK": &types.AttributeValueMemberN{Value: strconv.Itoa(id)},
+	}
+}
+
+func TestNewBatchGetInputs(t *testing.T) {
+	keys := make([]map[string]types.AttributeValue, 0, 260)
+	for i := 0; i < 250; i++ {
+		keys = append(keys, newBatchTestKey(i))
+	}
+	for i := 0; i < 10; i++ {
+		keys = append(keys, newBatchTestKey(i))
+	}
+	in, err := NewBatchGetInputs(BatchGet("Graph", keys...).
+		Keys("Audit", newBatchTestKey(1)).
+		Select("Graph", "name", "SK").
+		StrongConsistency("Graph").
+		Metrics(types.ReturnConsumedCapacityTotal))
+	require.NoError(t, err)
+	require.Len(t, in, 3)
+	assert.Len(t, in[0].RequestItems["Graph"].Keys, 100)
+	assert.Len(t, in[1].RequestItems["Graph"].Keys, 100)
+	assert.Len(t, in[2].RequestItems["Graph"].Keys, 50)
+	assert.Len(t, in[2].RequestItems["Audit"].Keys, 1)
+	assert.Equal(t, types.ReturnConsumedCapacityTotal, in[0].ReturnConsumedCapacity)
+
+	graph := in[0].RequestItems["Graph"]
+	assert.True(t, *graph.ConsistentRead)
+	assert.Equal(t, "#n0,#n1,#n2", *graph.ProjectionExpression)
+	assert.Equal(t, map[string]string{
+		"#n0": "name",
+		"#n1": "SK",
+		"#n2": "PK",
+	}, graph.ExpressionAttributeNames)
+	audit := in[2].RequestItems["Audit"]
+	assert.Nil(t, audit.ConsistentRead)
+	assert.Nil(t, audit.ProjectionExpression)
+}
+
+func TestBatchGetBuilder_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		batch *BatchGetBuilder
+		exp   error
+	}{
+		{
+			name:  "Missing keys",
+			batch: NewBatchGetBuilder(),
+			exp:   ErrMissingKeys,
+		},
+		{
+			name:  "Missing table",
+			batch: BatchGet("", newBatchTestKey(1)),
+			exp:   ErrMissingTable,
+		},
+		{
+			name:  "Empty key",
+			batch: BatchGet("Graph", map[string]types.AttributeValue{}),
+			exp:   ErrMissingKeys,
+		},
+		{
+			name: "Invalid key attribute",
+			batch: BatchGet("Graph", map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberBOOL{Value: true},
+			}),
+			exp: ErrMissingKeys,
+		},
+		{
+			name: "Inconsistent key attributes",
+			batch: BatchGet("Graph", newBatchTestKey(1), map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: "foo"},
+			}),
+			exp: ErrMissingKeys,
+		},
+		{
+			name:  "Invalid concurrency",
+			batch: BatchGet("Graph", newBatchTestKey(1)).Concurrency(0),
+			exp:   ErrInvalidOption,
+		},
+		{
+			name:  "Invalid backoff",
+			batch: BatchGet("Graph", newBatchTestKey(1)).Backoff(-1, time.Second),
+			exp:   ErrInvalidOption,
+		},
+		{
+			name:  "Nil key attribute",
+			batch: BatchGet("Graph", newBatchTestKey(1)).Keys("Audit", map[string]types.AttributeValue{"id": nil}),
+			exp:   ErrMissingKeys,
+		},
+		{
+			name: "Valid",
+			batch: BatchGet("Graph", newBatchTestKey(1), newBatchTestKey(1)).Keys("Audit", map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberB{Value: []byte("foo")},
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.batch.Validate(), tt.exp)
+		})
+	}
+
+	// table names are not attribute names
+	var errValidation ValidationError
+	require.True(t, errors.As(BatchGet("Graph", map[string]types.AttributeValue{}).Validate(), &errValidation))
+	assert.Empty(t, errValidation.Field)
+	assert.Contains(t, errValidation.Reason, "Graph")
+}
+
+// newBatchGetStub stores every item requested except ids multiple of 7. Every other request leaves half of its keys
+// unprocessed. Items are returned in reversed order.
+func newBatchGetStub(calls *int) batchGetFunc {
+	mu := sync.Mutex{}
+	return func(ctx context.Context, in *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+		mu.Lock()
+		*calls++
+		partial := *calls%2 == 1
+		mu.Unlock()
+		out := &dynamodb.BatchGetItemOutput{
+			Responses:       map[string][]map[string]types.AttributeValue{},
+			UnprocessedKeys: map[string]types.KeysAndAttributes{},
+		}
+		for table, req := range in.RequestItems {
+			keys := req.Keys
+			if partial && len(keys) > 1 {
+				out.UnprocessedKeys[table] = types.KeysAndAttributes{Keys: keys[len(keys)/2:]}
+				keys = keys[:len(keys)/2]
+			}
+			for i := len(keys) - 1; i >= 0; i-- {
+				id, _ := strconv.Atoi(keys[i]["SK"].(*types.AttributeValueMemberN).Value)
+				if id%7 == 0 {
+					continue
+				}
+				item := newBatchTestKey(id)
+				item["SK"] = &types.AttributeValueMemberN{Value: strconv.Itoa(id) + ".0"}
+				item["table"] = &types.AttributeValueMemberS{Value: table}
+				out.Responses[table] = append(out.Responses[table], item)
+			}
+		}
+		return out, nil
+	}
+}
+
+func TestBatchGetBuilder_ExecBatchGet(t *testing.T) {
+	b := NewBatchGetBuilder().Concurrency(3).Backoff(0, 0)
+	for i := 1; i <= 320; i++ {
+		b.Keys("Graph", newBatchTestKey(i))
+	}
+	b.Keys("Audit", newBatchTestKey(8), newBatchTestKey(14))
+	b.Keys("Graph", newBatchTestKey(1))
+
+	calls := 0
+	out, err := b.execBatchGet(context.Background(), newBatchGetStub(&calls))
+	require.NoError(t, err)
+	assert.Greater(t, calls, 4)
+	require.Len(t, out.Items, 323)
+	for i := 1; i <= 320; i++ {
+		if i%7 == 0 {
+			assert.Nil(t, out.Items[i-1])
+			continue
+		}
+		require.NotNil(t, out.Items[i-1])
+		assert.Equal(t, "STUDENT#"+strconv.Itoa(i), out.Items[i-1]["PK"].(*types.AttributeValueMemberS).Value)
+		assert.Equal(t, "Graph", out.Items[i-1]["table"].(*types.AttributeValueMemberS).Value)
+	}
+	assert.Equal(t, "Audit", out.Items[320]["table"].(*types.AttributeValueMemberS).Value)
+	assert.Nil(t, out.Items[321])
+	assert.Equal(t, out.Items[0], out.Items[322])
+}
+
+func TestBatchGetBuilder_ExecBatchGetErrors(t *testing.T) {
+	unprocessed := func(ctx context.Context, in *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+		return &dynamodb.BatchGetItemOutput{UnprocessedKeys: in.RequestItems}, nil
+	}
+	_, err := BatchGet("Graph", newBatchTestKey(1)).Retries(2).Backoff(0, 0).
+		execBatchGet(context.Background(), unprocessed)
+	assert.ErrorIs(t, err, ErrUnprocessedItems)
+
+	// keys multiple of 3 are never processed
+	partial := func(ctx context.Context, in *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+		out := &dynamodb.BatchGetItemOutput{
+			Responses:       map[string][]map[string]types.AttributeValue{},
+			UnprocessedKeys: map[string]types.KeysAndAttributes{},
+		}
+		for table, req := range in.RequestItems {
+			for _, key := range req.Keys {
+				if MustParseInt(key["SK"])%3 == 0 {
+					keys := out.UnprocessedKeys[table]
+					keys.Keys = append(keys.Keys, key)
+					out.UnprocessedKeys[table] = keys
+					continue
+				}
+				out.Responses[table] = append(out.Responses[table], key)
+			}
+		}
+		return out, nil
+	}
+	b := NewBatchGetBuilder().Concurrency(2).Retries(1).Backoff(0, 0)
+	for i := 1; i <= 150; i++ {
+		b.Keys("Graph", newBatchTestKey(i))
+	}
+	out, err := b.execBatchGet(context.Background(), partial)
+	assert.ErrorIs(t, err, ErrUnprocessedItems)
+	var errBatch BatchGetError
+	require.True(t, errors.As(err, &errBatch))
+	require.Len(t, errBatch.UnprocessedKeys["Graph"], 50)
+	for _, key := range errBatch.UnprocessedKeys["Graph"] {
+		assert.Zero(t, MustParseInt(key["SK"])%3)
+	}
+	require.Len(t, out.Items, 150)
+	for i := 1; i <= 150; i++ {
+		if i%3 == 0 {
+			assert.Nil(t, out.Items[i-1])
+			continue
+		}
+		assert.Equal(t, newBatchTestKey(i), out.Items[i-1])
+	}
+
+	errAPI := errors.New("api error")
+	failing := func(ctx context.Context, in *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+		return nil, errAPI
+	}
+	b = NewBatchGetBuilder().Concurrency(4)
+	for i := 0; i < 1000; i++ {
+		b.Keys("Graph", newBatchTestKey(i))
+	}
+	_, err = b.execBatchGet(context.Background(), failing)
+	assert.ErrorIs(t, err, errAPI)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = BatchGet("Graph", newBatchTestKey(1)).execBatchGet(ctx, unprocessed)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBatchRetry_Delay(t *testing.T) {
+	r := batchRetry{
+		baseDelay: 10 * time.Millisecond,
+		maxDelay:  100 * time.Millisecond,
+	}
+	for attempt := 0; attempt < 64; attempt++ {
+		d := r.delay(attempt)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, r.maxDelay)
+		if attempt == 0 {
+			assert.LessOrEqual(t, d, r.baseDelay)
+		}
+	}
+	assert.Zero(t, batchRetry{}.delay(3))
+}
@@ -0,0 +1,405 @@
+package dynamoql
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// BatchWriteMaxItems maximum number of write requests accepted by a single BatchWriteItem API request.
+const BatchWriteMaxItems = 25
+
+// ErrBatchWriterClosed the BatchWriter was closed and does not accept write requests anymore.
+var ErrBatchWriterClosed = errors.New("dynamoql: Batch writer closed")
+
+// BatchWriteError a write request (put or delete) which could not be written by a BatchWriter.
+type BatchWriteError struct {
+	Request types.WriteRequest
+	Err     error
+}
+
+var _ error = BatchWriteError{}
+
+func (e BatchWriteError) Error() string {
+	return "dynamoql: Batch write failed: " + e.Err.Error()
+}
+
+// Unwrap retrieves the underlying error.
+func (e BatchWriteError) Unwrap() error {
+	return e.Err
+}
+
+// batchWriteFunc executes a BatchWriteItem API operation.
+type batchWriteFunc func(context.Context, *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error)
+
+type batchWriteChunk struct {
+	ctx      context.Context
+	requests []types.WriteRequest
+}
+
+// BatchWriter buffers put and delete requests of an Amazon DynamoDB table and writes them using the BatchWriteItem
+// API.
+//
+// Requests are written in chunks of BatchWriteMaxItems requests by a pool of workers (goroutines), hence no ordering
+// is guaranteed. Unprocessed requests are retried using exponential backoff with jitter. Requests which could not
+// be written are reported as BatchWriteError by Errors.
+//
+// A chunk is written using the context.Context of the call which filled it (Put, Delete or Flush). As the
+// BatchWriteItem API rejects chunks with multiple requests for the same item, a buffered request is replaced by a
+// later request for the same primary key. Primary key attribute names are taken from KeyAttributes or, if not set,
+// from the first NodeSchema put request or delete request.
+//
+// Some example for using BatchWriter:
+//
+//	w := dynamoql.NewBatchWriter(c, "Graph", 4)
+//	defer w.Close(ctx)
+//	for _, student := range students {
+//		if err := w.Put(ctx, student); err != nil {
+//			break
+//		}
+//	}
+//	if err := w.Flush(ctx); err != nil {
+//		// handle failed requests using w.Errors()
+//	}
+type BatchWriter struct {
+	fn        batchWriteFunc
+	table     string
+	workers   int
+	retry     batchRetry
+	startOnce sync.Once
+	chunks    chan batchWriteChunk
+	workersWg sync.WaitGroup
+	mu        sync.Mutex
+	buf       []types.WriteRequest
+	pending   int
+	idle      chan struct{}
+	bufKeys   map[string]int
+	keyNames  []string
+	closed    bool
+	errs      []error
+	errOffset int
+	itemCount int
+}
+
+// NewBatchWriter allocates a BatchWriter with required internal components.
+//
+// The number of workers sets the maximum number of BatchWriteItem API requests running concurrently. A single
+// worker is started if not greater than zero.
+func NewBatchWriter(c *dynamodb.Client, table string, workers int) *BatchWriter {
+	return newBatchWriter(func(ctx context.Context, in *dynamodb.BatchWriteItemInput) (
+		*dynamodb.BatchWriteItemOutput, error) {
+		return c.BatchWriteItem(ctx, in)
+	}, table, workers)
+}
+
+func newBatchWriter(fn batchWriteFunc, table string, workers int) *BatchWriter {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &BatchWriter{
+		fn:      fn,
+		table:   table,
+		workers: workers,
+		retry:   newBatchRetry(),
+		buf:     make([]types.WriteRequest, 0, BatchWriteMaxItems),
+		bufKeys: make(map[string]int, BatchWriteMaxItems),
+	}
+}
+
+// Retries sets the maximum number of consecutive retries of unprocessed requests without any progress. Defaults to
+// DefaultBatchRetries. MUST be called before writing any request.
+func (w *BatchWriter) Retries(n int) *BatchWriter {
+	w.retry.maxRetries = n
+	return w
+}
+
+// Backoff sets base and maximum delays of the exponential backoff used to retry unprocessed requests. Defaults to
+// DefaultBatchBaseDelay and DefaultBatchMaxDelay. MUST be called before writing any request.
+func (w *BatchWriter) Backoff(base, max time.Duration) *BatchWriter {
+	w.retry.baseDelay = base
+	w.retry.maxDelay = max
+	return w
+}
+
+// KeyAttributes sets primary key attribute names of the table, used to detect multiple requests for the same item.
+// MUST be called before writing any request.
+func (w *BatchWriter) KeyAttributes(names ...string) *BatchWriter {
+	w.keyNames = make([]string, len(names))
+	copy(w.keyNames, names)
+	sort.Strings(w.keyNames)
+	return w
+}
+
+// Count returns the count of each request written by a BatchWriter instance.
+func (w *BatchWriter) Count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.itemCount
+}
+
+// Errors retrieves every request which could not be written (BatchWriteError).
+func (w *BatchWriter) Errors() []error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.errs) == 0 {
+		return nil
+	}
+	errs := make([]error, len(w.errs))
+	copy(errs, w.errs)
+	return errs
+}
+
+// Put buffers a put request of the given schema. Blocks if the buffer is full until a worker is available.
+//
+// Returns a ValidationError if the schema is marshaled into an empty item or the error returned by the schema
+// Marshaler.
+func (w *BatchWriter) Put(ctx context.Context, v Marshaler) error {
+	item, err := v.MarshalDynamoDB()
+	if err != nil {
+		return err
+	} else if len(item) == 0 {
+		return newValidationError(ErrMissingKeys, "",
+			"schema marshaled into an empty item, primary key attributes are required")
+	}
+	var keys map[string]types.AttributeValue
+	if node, ok := v.(NodeSchema); ok {
+		keys = node.GetKeys()
+	}
+	return w.write(ctx, types.WriteRequest{
+		PutRequest: &types.PutRequest{Item: item},
+	}, keys)
+}
+
+// Delete buffers a delete request of the item with the given primary key. Blocks if the buffer is full until a
+// worker is available.
+//
+// Returns a ValidationError if keys are empty.
+func (w *BatchWriter) Delete(ctx context.Context, keys map[string]types.AttributeValue) error {
+	if len(keys) == 0 {
+		return newValidationError(ErrMissingKeys, "", "item primary key is required")
+	}
+	return w.write(ctx, types.WriteRequest{
+		DeleteRequest: &types.DeleteRequest{Key: keys},
+	}, keys)
+}
+
+// write buffers the given request, replacing any buffered request for the same primary key (keys). If keys are
+// nil, primary key attributes are taken from the put request item.
+func (w *BatchWriter) write(ctx context.Context, req types.WriteRequest, keys map[string]types.AttributeValue) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrBatchWriterClosed
+	}
+	if id := w.requestKey(req, keys); id == "" {
+		w.buf = append(w.buf, req)
+	} else if i, ok := w.bufKeys[id]; ok {
+		w.buf[i] = req
+		w.mu.Unlock()
+		return nil
+	} else {
+		w.bufKeys[id] = len(w.buf)
+		w.buf = append(w.buf, req)
+	}
+	if len(w.buf) < BatchWriteMaxItems {
+		w.mu.Unlock()
+		return nil
+	}
+	requests := w.takeBuffer()
+	w.mu.Unlock()
+	return w.send(ctx, requests)
+}
+
+// requestKey retrieves the identity of the item written by the given request. Returns an empty string if primary key
+// attribute names are unknown. MUST be called while holding the lock.
+func (w *BatchWriter) requestKey(req types.WriteRequest, keys map[string]types.AttributeValue) string {
+	if len(keys) > 0 && len(w.keyNames) == 0 {
+		w.keyNames = keyAttributeNames(keys)
+	}
+	if len(w.keyNames) == 0 {
+		return ""
+	} else if len(keys) == 0 && req.PutRequest != nil {
+		keys = req.PutRequest.Item
+	}
+	return keyIdentity(w.table, w.keyNames, keys)
+}
+
+// takeBuffer retrieves buffered requests and resets the buffer, registering them as pending. MUST be called while
+// holding the lock.
+func (w *BatchWriter) takeBuffer() []types.WriteRequest {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	requests := w.buf
+	w.buf = make([]types.WriteRequest, 0, BatchWriteMaxItems)
+	w.bufKeys = make(map[string]int, BatchWriteMaxItems)
+	w.pending++
+	return requests
+}
+
+// send hands the given requests over to a worker. Requests are reported as failed if ctx is done before any worker
+// is available.
+func (w *BatchWriter) send(ctx context.Context, requests []types.WriteRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	w.startOnce.Do(w.start)
+	select {
+	case <-ctx.Done():
+		w.done(requests, 0, ctx.Err())
+		return ctx.Err()
+	case w.chunks <- batchWriteChunk{ctx: ctx, requests: requests}:
+		return nil
+	}
+}
+
+// Flush writes every buffered request and blocks until every pending request has been either written or reported
+// as failed.
+//
+// Returns the first failure (BatchWriteError) registered since the previous Flush or Close call, use Errors to
+// retrieve every failure.
+func (w *BatchWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	requests := w.takeBuffer()
+	w.mu.Unlock()
+	if err := w.send(ctx, requests); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if w.pending > 0 && w.idle == nil {
+		w.idle = make(chan struct{})
+	}
+	idle := w.idle
+	w.mu.Unlock()
+	if idle != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-idle:
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.errOffset >= len(w.errs) {
+		return nil
+	}
+	err := w.errs[w.errOffset]
+	w.errOffset = len(w.errs)
+	return err
+}
+
+// Close flushes every buffered request and stops every worker. Further write requests are rejected with
+// ErrBatchWriterClosed.
+//
+// Returns the first failure (BatchWriteError) registered since the previous Flush call, use Errors to retrieve every
+// failure. If ctx is done before every pending request is written, workers are stopped in background.
+func (w *BatchWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+	err := w.Flush(ctx)
+	if ctx.Err() != nil {
+		go w.stop()
+		return err
+	}
+	w.stop()
+	return err
+}
+
+// stop waits for pending chunks and stops every worker. As the BatchWriter is closed, no chunks are sent afterwards.
+func (w *BatchWriter) stop() {
+	w.mu.Lock()
+	if w.pending > 0 && w.idle == nil {
+		w.idle = make(chan struct{})
+	}
+	idle := w.idle
+	w.mu.Unlock()
+	if idle != nil {
+		<-idle
+	}
+	w.startOnce.Do(func() {})
+	if w.chunks != nil {
+		close(w.chunks)
+		w.workersWg.Wait()
+	}
+}
+
+// start spawns the pool of workers.
+func (w *BatchWriter) start() {
+	w.chunks = make(chan batchWriteChunk)
+	w.workersWg.Add(w.workers)
+	for i := 0; i < w.workers; i++ {
+		go func() {
+			defer w.workersWg.Done()
+			for chunk := range w.chunks {
+				unprocessed, err := w.writeChunk(chunk)
+				w.done(unprocessed, len(chunk.requests)-len(unprocessed), err)
+			}
+		}()
+	}
+}
+
+// writeChunk executes a BatchWriteItem API operation, retrying unprocessed requests until every request is written.
+//
+// Returns requests which could not be written and the reason.
+func (w *BatchWriter) writeChunk(chunk batchWriteChunk) ([]types.WriteRequest, error) {
+	in := dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			w.table: chunk.requests,
+		},
+	}
+	attempt := 0
+	for {
+		out, err := w.fn(chunk.ctx, &in)
+		if err != nil {
+			return in.RequestItems[w.table], err
+		}
+		unprocessed := out.UnprocessedItems[w.table]
+		if len(unprocessed) == 0 {
+			return nil, nil
+		} else if len(unprocessed) < len(in.RequestItems[w.table]) {
+			// retries are only exhausted by consecutive operations without progress
+			attempt = 0
+		}
+		if attempt >= w.retry.maxRetries {
+			return unprocessed, ErrUnprocessedItems
+		} else if err = w.retry.wait(chunk.ctx, attempt); err != nil {
+			return unprocessed, err
+		}
+		attempt++
+		in.RequestItems = map[string][]types.WriteRequest{
+			w.table: unprocessed,
+		}
+	}
+}
+
+// done registers the result of a pending chunk, notifying Flush callers once no chunks are pending.
+func (w *BatchWriter) done(failed []types.WriteRequest, written int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.itemCount += written
+	if err != nil {
+		for _, req := range failed {
+			w.errs = append(w.errs, BatchWriteError{
+				Request: req,
+				Err:     err,
+			})
+		}
+	}
+	w.pending--
+	if w.pending == 0 && w.idle != nil {
+		close(w.idle)
+		w.idle = nil
+	}
+}
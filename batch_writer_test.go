@@ -0,0 +1,203 @@
+package dynamoql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batchTestItem map[string]types.AttributeValue
+
+func (i batchTestItem) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
+	return i, nil
+}
+
+// batchWriteStub stores every written request id. Every other request leaves a third of its requests unprocessed.
+// Rejects requests with multiple writes for the same item, as the BatchWriteItem API does.
+type batchWriteStub struct {
+	mu       sync.Mutex
+	calls    int
+	maxItems int
+	written  map[string]int
+}
+
+func (s *batchWriteStub) write(_ context.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput,
+	error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	requests := in.RequestItems["Graph"]
+	if len(requests) > s.maxItems {
+		s.maxItems = len(requests)
+	}
+	keys := make(map[string]struct{}, len(requests))
+	for _, req := range requests {
+		var key string
+		if req.PutRequest != nil {
+			key = MustParseString(req.PutRequest.Item["PK"])
+		} else {
+			key = MustParseString(req.DeleteRequest.Key["PK"])
+		}
+		if _, ok := keys[key]; ok {
+			return nil, errors.New("ValidationException: Provided list of item keys contains duplicates")
+		}
+		keys[key] = struct{}{}
+	}
+	out := &dynamodb.BatchWriteItemOutput{}
+	if s.calls%2 == 1 && len(requests) > 2 {
+		out.UnprocessedItems = map[string][]types.WriteRequest{
+			"Graph": requests[len(requests)/3*2:],
+		}
+		requests = requests[:len(requests)/3*2]
+	}
+	for _, req := range requests {
+		if req.PutRequest != nil {
+			s.written["put:"+MustParseString(req.PutRequest.Item["PK"])]++
+			continue
+		}
+		s.written["delete:"+MustParseString(req.DeleteRequest.Key["PK"])]++
+	}
+	return out, nil
+}
+
+func TestBatchWriter(t *testing.T) {
+	stub := &batchWriteStub{written: map[string]int{}}
+	w := newBatchWriter(stub.write, "Graph", 4).Backoff(0, 0)
+	ctx := context.Background()
+	for i := 0; i < 1000; i++ {
+		id := &types.AttributeValueMemberS{Value: strconv.Itoa(i)}
+		require.NoError(t, w.Put(ctx, batchTestItem{"PK": id}))
+		require.NoError(t, w.Delete(ctx, map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: "-" + id.Value},
+		}))
+		if i == 500 {
+			require.NoError(t, w.Flush(ctx))
+			assert.Equal(t, 1002, w.Count())
+		}
+	}
+	require.NoError(t, w.Close(ctx))
+	assert.Equal(t, 2000, w.Count())
+	assert.Empty(t, w.Errors())
+	assert.Equal(t, BatchWriteMaxItems, stub.maxItems)
+	require.Len(t, stub.written, 2000)
+	for k, n := range stub.written {
+		assert.Equal(t, 1, n, k)
+	}
+
+	assert.ErrorIs(t, w.Put(ctx, batchTestItem{"PK": &types.AttributeValueMemberS{Value: "foo"}}),
+		ErrBatchWriterClosed)
+	assert.NoError(t, w.Close(ctx))
+}
+
+func TestBatchWriter_Errors(t *testing.T) {
+	ctx := context.Background()
+	w := newBatchWriter(func(_ context.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput,
+		error) {
+		return &dynamodb.BatchWriteItemOutput{UnprocessedItems: in.RequestItems}, nil
+	}, "Graph", 2).Retries(1).Backoff(0, 0)
+	assert.ErrorIs(t, w.Put(ctx, batchTestItem{}), ErrMissingKeys)
+	assert.ErrorIs(t, w.Delete(ctx, nil), ErrMissingKeys)
+	for i := 0; i < 30; i++ {
+		require.NoError(t, w.Put(ctx, batchTestItem{"PK": &types.AttributeValueMemberS{Value: strconv.Itoa(i)}}))
+	}
+	err := w.Close(ctx)
+	assert.ErrorIs(t, err, ErrUnprocessedItems)
+	var errWrite BatchWriteError
+	require.True(t, errors.As(err, &errWrite))
+	assert.NotNil(t, errWrite.Request.PutRequest)
+	assert.Len(t, w.Errors(), 30)
+	assert.Zero(t, w.Count())
+
+	errAPI := errors.New("api error")
+	w = newBatchWriter(func(_ context.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput,
+		error) {
+		return nil, errAPI
+	}, "Graph", 0)
+	require.NoError(t, w.Delete(ctx, map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "1"}}))
+	assert.ErrorIs(t, w.Flush(ctx), errAPI)
+	errs := w.Errors()
+	assert.Len(t, errs, 1)
+	errs[0] = nil // a copy is retrieved
+	assert.ErrorIs(t, w.Errors()[0], errAPI)
+	// failures are reported once
+	assert.NoError(t, w.Flush(ctx))
+	assert.NoError(t, w.Close(ctx))
+
+	fail := true
+	w = newBatchWriter(func(_ context.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput,
+		error) {
+		if fail {
+			return nil, errAPI
+		}
+		return &dynamodb.BatchWriteItemOutput{}, nil
+	}, "Graph", 1)
+	require.NoError(t, w.Put(ctx, batchTestItem{"PK": &types.AttributeValueMemberS{Value: "1"}}))
+	assert.ErrorIs(t, w.Flush(ctx), errAPI)
+	fail = false
+	require.NoError(t, w.Put(ctx, batchTestItem{"PK": &types.AttributeValueMemberS{Value: "2"}}))
+	assert.NoError(t, w.Flush(ctx))
+	assert.Equal(t, 1, w.Count())
+	assert.Len(t, w.Errors(), 1)
+	assert.NoError(t, w.Close(ctx))
+}
+
+func TestBatchWriter_DuplicateKeys(t *testing.T) {
+	ctx := context.Background()
+	var written []types.WriteRequest
+	fn := func(_ context.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+		written = append(written, in.RequestItems["Graph"]...)
+		return &dynamodb.BatchWriteItemOutput{}, nil
+	}
+	newItem := func(id, name string) batchTestItem {
+		return batchTestItem{
+			"PK":   &types.AttributeValueMemberS{Value: id},
+			"SK":   &types.AttributeValueMemberS{Value: "root"},
+			"name": &types.AttributeValueMemberS{Value: name},
+		}
+	}
+	newKeys := func(id string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: id},
+			"SK": &types.AttributeValueMemberS{Value: "root"},
+		}
+	}
+
+	w := newBatchWriter(fn, "Graph", 1).KeyAttributes("PK", "SK")
+	require.NoError(t, w.Put(ctx, newItem("1", "foo")))
+	require.NoError(t, w.Put(ctx, newItem("1", "bar")))
+	require.NoError(t, w.Put(ctx, newItem("2", "foo")))
+	require.NoError(t, w.Delete(ctx, newKeys("2")))
+	require.NoError(t, w.Close(ctx))
+	require.Len(t, written, 2)
+	assert.Equal(t, "bar", MustParseString(written[0].PutRequest.Item["name"]))
+	assert.Equal(t, newKeys("2"), written[1].DeleteRequest.Key)
+	assert.Equal(t, 2, w.Count())
+
+	// primary key attribute names are taken from delete requests
+	written = nil
+	w = newBatchWriter(fn, "Graph", 1)
+	require.NoError(t, w.Delete(ctx, newKeys("1")))
+	require.NoError(t, w.Put(ctx, newItem("1", "foo")))
+	require.NoError(t, w.Put(ctx, newItem("2", "foo")))
+	require.NoError(t, w.Put(ctx, newItem("2", "bar")))
+	require.NoError(t, w.Close(ctx))
+	require.Len(t, written, 2)
+	assert.Equal(t, newItem("1", "foo"), batchTestItem(written[0].PutRequest.Item))
+	assert.Equal(t, newItem("2", "bar"), batchTestItem(written[1].PutRequest.Item))
+
+	// numbers are compared as Amazon DynamoDB does
+	written = nil
+	w = newBatchWriter(fn, "Graph", 1).KeyAttributes("id")
+	require.NoError(t, w.Put(ctx, batchTestItem{"id": &types.AttributeValueMemberN{Value: "1.50"}}))
+	require.NoError(t, w.Put(ctx, batchTestItem{"id": &types.AttributeValueMemberN{Value: "1.5"}}))
+	require.NoError(t, w.Close(ctx))
+	require.Len(t, written, 1)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "1.5"}, written[0].PutRequest.Item["id"])
+}
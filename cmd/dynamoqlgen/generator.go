@@ -0,0 +1,507 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	structTagName     = "dynamoql"
+	defaultImportPath = "github.com/maestre3d/dynamoql-go"
+)
+
+var (
+	errTypeNotFound      = errors.New("type not found")
+	errUnsupportedType   = errors.New("unsupported type")
+	errInvalidStructTag  = errors.New("invalid struct tag")
+	errUnsupportedEmbeds = errors.New("embedded fields are not supported")
+)
+
+// scalarParsers dynamoql parser suffixes for each supported Go type (e.g. string -> ParseString).
+var scalarParsers = map[string]string{
+	"string":        "String",
+	"bool":          "Bool",
+	"int":           "Int",
+	"int8":          "Int8",
+	"int16":         "Int16",
+	"int32":         "Int32",
+	"int64":         "Int64",
+	"uint":          "Uint",
+	"uint8":         "Uint8",
+	"uint16":        "Uint16",
+	"uint32":        "Uint32",
+	"uint64":        "Uint64",
+	"float32":       "Float32",
+	"float64":       "Float64",
+	"[]byte":        "Binary",
+	"time.Time":     "Time",
+	"time.Duration": "Duration",
+	// arbitrary-precision numbers, formatted using dynamoql.MarshalAttribute as they might exceed number limits
+	"dynamoql.Decimal": "Decimal",
+	"*big.Int":         "BigInt",
+	"*big.Float":       "BigFloat",
+	"*big.Rat":         "BigRat",
+}
+
+// numberParsers dynamoql parser suffixes of arbitrary-precision number types.
+var numberParsers = map[string]bool{
+	"Decimal":  true,
+	"BigInt":   true,
+	"BigFloat": true,
+	"BigRat":   true,
+}
+
+// setParsers dynamoql parser suffixes for each Go type supported by the set option.
+var setParsers = map[string]string{
+	"[]string":  "StringSet",
+	"[]int":     "IntSet",
+	"[]uint":    "UintSet",
+	"[]float32": "Float32Set",
+	"[]float64": "Float64Set",
+	"[][]byte":  "BinarySet",
+}
+
+// timeEncodings dynamoql.TimeEncoding constant names of each struct tag time option.
+var timeEncodings = map[string]string{
+	"unixtime":    "TimeUnix",
+	"unixmilli":   "TimeUnixMilli",
+	"rfc3339nano": "TimeRFC3339Nano",
+}
+
+// config generator options.
+type config struct {
+	// dir directory of the package containing the types.
+	dir string
+	// typeNames names of the struct types to generate methods for.
+	typeNames []string
+	// importPath import path of dynamoql package. If empty, the path imported by the package files is used,
+	// defaultImportPath otherwise.
+	importPath string
+	// command used to invoke the generator, written into the generated file header.
+	command string
+}
+
+// field a struct field converted from/into an Amazon DynamoDB attribute.
+type field struct {
+	name      string
+	attribute string
+	goType    string
+	parser    string
+	omitEmpty bool
+	set       bool
+	// timeEncoding dynamoql.TimeEncoding constant name, empty if time is formatted using dynamoql.DefaultTimeFormat.
+	timeEncoding string
+	key          bool
+	composite    string
+	// hasComposite distinguishes composite keys with an empty prefix from regular fields.
+	hasComposite bool
+}
+
+// schemaType a struct type to generate methods for.
+type schemaType struct {
+	name      string
+	receiver  string
+	fields    []field
+	hasSchema bool
+	schema    string
+}
+
+// generate parses the package at cfg.dir and generates MarshalDynamoDB, UnmarshalDynamoDB, GetKeys and GetName
+// methods for each of cfg.typeNames, returning the formatted source file.
+func generate(cfg config) ([]byte, error) {
+	files, pkgName, err := parsePackage(cfg.dir)
+	if err != nil {
+		return nil, err
+	}
+	importPath := cfg.importPath
+	if importPath == "" {
+		importPath = findImportPath(files)
+	}
+
+	schemas := make([]schemaType, 0, len(cfg.typeNames))
+	for _, name := range cfg.typeNames {
+		st, err := findStruct(files, name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		schema, err := newSchemaType(files, name, st)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	g := &generator{}
+	g.printf("// Code generated by %q; DO NOT EDIT.\n\n", cfg.command)
+	g.printf("package %s\n\n", pkgName)
+	g.printImports(importPath)
+	for _, schema := range schemas {
+		g.printSchema(schema)
+	}
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w", err)
+	}
+	return src, nil
+}
+
+// parsePackage parses non-test Go files of dir.
+func parsePackage(dir string) ([]*ast.File, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(entries))
+	pkgName := ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, "", err
+		}
+		pkgName = f.Name.Name
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, "", fmt.Errorf("no Go files found in %s", dir)
+	}
+	return files, pkgName, nil
+}
+
+// findImportPath retrieves the dynamoql import path used by files, defaultImportPath if not found.
+func findImportPath(files []*ast.File) string {
+	for _, f := range files {
+		for _, spec := range f.Imports {
+			p, _ := strconv.Unquote(spec.Path.Value)
+			if base := path.Base(p); base == "dynamoql" || base == "dynamoql-go" {
+				return p
+			}
+		}
+	}
+	return defaultImportPath
+}
+
+func findStruct(files []*ast.File, name string) (*ast.StructType, error) {
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if typeSpec.Name.Name != name {
+					continue
+				}
+				st, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					return nil, errUnsupportedType
+				}
+				return st, nil
+			}
+		}
+	}
+	return nil, errTypeNotFound
+}
+
+// newSchemaType registers the exported fields of st, flattening untagged embedded structs declared in files as
+// dynamoql.Marshal does.
+func newSchemaType(files []*ast.File, name string, st *ast.StructType) (schemaType, error) {
+	schema := schemaType{
+		name:     name,
+		receiver: string(unicode.ToLower([]rune(name)[0])),
+	}
+	if err := schema.addFields(files, st, ""); err != nil {
+		return schemaType{}, err
+	}
+	return schema, nil
+}
+
+// addFields registers the fields of st. prefix is the selector of the embedded struct holding st, if any.
+func (s *schemaType) addFields(files []*ast.File, st *ast.StructType, prefix string) error {
+	for _, astField := range st.Fields.List {
+		tag := ""
+		if astField.Tag != nil {
+			tag, _ = strconv.Unquote(astField.Tag.Value)
+			tag = reflect.StructTag(tag).Get(structTagName)
+		}
+		if tag == "-" {
+			continue
+		}
+		goType := types.ExprString(astField.Type)
+		if len(astField.Names) == 0 {
+			if err := s.addEmbedded(files, astField, tag, prefix); err != nil {
+				return fmt.Errorf("%s: %w", goType, err)
+			}
+			continue
+		}
+		for _, ident := range astField.Names {
+			f, isSchema, err := newField(prefix+ident.Name, goType, tag)
+			if !isSchema && !ident.IsExported() {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("%s: %w", ident.Name, err)
+			} else if isSchema {
+				s.hasSchema = true
+				s.schema = f.attribute
+				continue
+			}
+			s.fields = append(s.fields, f)
+		}
+	}
+	return nil
+}
+
+// addEmbedded flattens the fields of an embedded struct. Only untagged (or unnamed) structs declared in the same
+// package are flattened; as dynamoql.Marshal stores other embedded types as regular (nested) attributes, which are not
+// supported by generated methods, errUnsupportedEmbeds is returned instead.
+func (s *schemaType) addEmbedded(files []*ast.File, astField *ast.Field, tag, prefix string) error {
+	ident, ok := astField.Type.(*ast.Ident)
+	if !ok || strings.Split(tag, ",")[0] != "" {
+		return errUnsupportedEmbeds
+	}
+	st, err := findStruct(files, ident.Name)
+	if err != nil {
+		return errUnsupportedEmbeds
+	}
+	return s.addFields(files, st, prefix+ident.Name+".")
+}
+
+// newField parses the struct tag of a field, verifying both options and field type are supported. Tag grammar is
+// shared with dynamoql.Marshal (see dynamoql.StructTagName).
+func newField(name, goType, tag string) (f field, isSchema bool, err error) {
+	spl := strings.Split(tag, ",")
+	f = field{
+		name:      name,
+		attribute: spl[0],
+		goType:    goType,
+	}
+	for _, opt := range spl[1:] {
+		switch {
+		case opt == "omitempty":
+			f.omitEmpty = true
+		case opt == "set":
+			f.set = true
+		case timeEncodings[opt] != "":
+			f.timeEncoding = timeEncodings[opt]
+		case opt == "key":
+			f.key = true
+		case opt == "schema":
+			isSchema = true
+		case strings.HasPrefix(opt, "composite="):
+			f.hasComposite = true
+			f.composite = strings.TrimPrefix(opt, "composite=")
+		default:
+			return field{}, false, fmt.Errorf("%w: unknown option %q", errInvalidStructTag, opt)
+		}
+	}
+	if isSchema {
+		return f, true, nil
+	} else if f.attribute == "" {
+		f.attribute = name
+	}
+
+	switch {
+	case f.set:
+		f.parser = setParsers[goType]
+	case f.timeEncoding != "" && goType != "time.Time":
+		return field{}, false, fmt.Errorf("%w: time encoding options require time.Time", errInvalidStructTag)
+	case f.hasComposite && goType != "string":
+		return field{}, false, fmt.Errorf("%w: composite option requires string", errInvalidStructTag)
+	default:
+		f.parser = scalarParsers[goType]
+	}
+	if f.parser == "" {
+		return field{}, false, fmt.Errorf("%w %s", errUnsupportedType, goType)
+	}
+	return f, false, nil
+}
+
+// generator accumulates the generated source file.
+type generator struct {
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) printImports(importPath string) {
+	g.printf("import (\n")
+	g.printf("\t\"github.com/aws/aws-sdk-go-v2/service/dynamodb/types\"\n")
+	if path.Base(importPath) == "dynamoql" {
+		g.printf("\t%q\n", importPath)
+	} else {
+		g.printf("\tdynamoql %q\n", importPath)
+	}
+	g.printf(")\n")
+}
+
+func (g *generator) printSchema(s schemaType) {
+	if s.hasSchema && s.schema != "" {
+		g.printf("\nfunc (%s %s) GetName() string {\n", s.receiver, s.name)
+		g.printf("\treturn %q\n}\n", s.schema)
+	}
+	g.printGetKeys(s)
+	g.printMarshal(s)
+	g.printUnmarshal(s)
+}
+
+func (g *generator) printGetKeys(s schemaType) {
+	keys := make([]field, 0, 2)
+	for _, f := range s.fields {
+		if f.key {
+			keys = append(keys, f)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	g.printf("\nfunc (%s %s) GetKeys() map[string]types.AttributeValue {\n", s.receiver, s.name)
+	g.printf("\treturn map[string]types.AttributeValue{\n")
+	for _, f := range keys {
+		g.printf("\t\t%q: %s,\n", f.attribute, formatExpr(s.receiver, f))
+	}
+	g.printf("\t}\n}\n")
+}
+
+func (g *generator) printMarshal(s schemaType) {
+	g.printf("\nfunc (%s %s) MarshalDynamoDB() (map[string]types.AttributeValue, error) {\n", s.receiver, s.name)
+	optional := make([]field, 0)
+	required := make([]field, 0, len(s.fields))
+	hasChecked := false
+	for _, f := range s.fields {
+		isChecked := isCheckedField(f)
+		hasChecked = hasChecked || isChecked
+		if f.omitEmpty || isChecked {
+			optional = append(optional, f)
+			continue
+		}
+		required = append(required, f)
+	}
+	if len(optional) == 0 {
+		g.printf("\treturn map[string]types.AttributeValue{\n")
+	} else {
+		g.printf("\titem := map[string]types.AttributeValue{\n")
+	}
+	for _, f := range required {
+		g.printf("\t\t%q: %s,\n", f.attribute, formatExpr(s.receiver, f))
+	}
+	if s.hasSchema {
+		g.printf("\t\tdynamoql.DefaultSchemaField: dynamoql.FormatAttribute(%s.GetName()),\n", s.receiver)
+	}
+	if len(optional) == 0 {
+		g.printf("\t}, nil\n}\n")
+		return
+	}
+	g.printf("\t}\n")
+	if hasChecked {
+		g.printf("\tvar err error\n")
+	}
+	for _, f := range optional {
+		switch {
+		case isCheckedField(f) && f.omitEmpty:
+			g.printf("\tif %s {\n", notEmptyExpr(s.receiver, f))
+			g.printCheckedAttribute(s.receiver, f, "\t\t")
+			g.printf("\t}\n")
+		case isCheckedField(f):
+			g.printCheckedAttribute(s.receiver, f, "\t")
+		default:
+			g.printf("\tif %s {\n", notEmptyExpr(s.receiver, f))
+			g.printf("\t\titem[%q] = %s\n\t}\n", f.attribute, formatExpr(s.receiver, f))
+		}
+	}
+	g.printf("\treturn item, nil\n}\n")
+}
+
+// isCheckedField indicates if converting the field f might fail, either as arbitrary-precision numbers might exceed
+// number limits or as empty sets are not accepted by Amazon DynamoDB (dynamoql.ErrEmptySet).
+func isCheckedField(f field) bool {
+	return numberParsers[f.parser] || (f.set && !f.omitEmpty)
+}
+
+// printCheckedAttribute prints the statement storing the field f of receiver r into item, returning an error if
+// dynamoql.MarshalAttribute fails (see isCheckedField).
+func (g *generator) printCheckedAttribute(r string, f field, indent string) {
+	g.printf("%sif item[%q], err = dynamoql.MarshalAttribute(%s.%s); err != nil {\n", indent, f.attribute, r, f.name)
+	g.printf("%s\treturn nil, dynamoql.AttributeError{Attribute: %q, Err: err}\n%s}\n", indent, f.attribute, indent)
+}
+
+func (g *generator) printUnmarshal(s schemaType) {
+	g.printf("\nfunc (%s *%s) UnmarshalDynamoDB(item map[string]types.AttributeValue) error {\n", s.receiver, s.name)
+	if s.hasSchema {
+		g.printf("\tif attr, ok := item[dynamoql.DefaultSchemaField]; ok {\n")
+		g.printf("\t\tif name, _ := dynamoql.ParseString(attr); name != %s.GetName() {\n", s.receiver)
+		g.printf("\t\t\treturn dynamoql.AttributeError{Attribute: dynamoql.DefaultSchemaField, " +
+			"Err: dynamoql.ErrInvalidSchema}\n\t\t}\n\t}\n")
+	}
+	for _, f := range s.fields {
+		g.printf("\tif attr, ok := item[%q]; ok {\n", f.attribute)
+		g.printf("\t\tval, err := %s\n", parseExpr(f))
+		g.printf("\t\tif err != nil {\n")
+		g.printf("\t\t\treturn dynamoql.AttributeError{Attribute: %q, Err: err}\n\t\t}\n", f.attribute)
+		switch {
+		case f.hasComposite:
+			g.printf("\t\t%s.%s = dynamoql.ParseCompositeKey(val)\n", s.receiver, f.name)
+		default:
+			g.printf("\t\t%s.%s = val\n", s.receiver, f.name)
+		}
+		g.printf("\t}\n")
+	}
+	g.printf("\treturn nil\n}\n")
+}
+
+// formatExpr expression converting the field f of receiver r into an Amazon DynamoDB attribute.
+func formatExpr(r string, f field) string {
+	switch {
+	case f.hasComposite:
+		return fmt.Sprintf("dynamoql.FormatAttribute(dynamoql.NewCompositeKey(%q, %s.%s))", f.composite, r, f.name)
+	case f.timeEncoding != "":
+		return fmt.Sprintf("dynamoql.FormatTime(%s.%s, dynamoql.%s)", r, f.name, f.timeEncoding)
+	default:
+		return fmt.Sprintf("dynamoql.FormatAttribute(%s.%s)", r, f.name)
+	}
+}
+
+// parseExpr expression converting the attribute attr into the type of field f.
+func parseExpr(f field) string {
+	if f.timeEncoding != "" {
+		return "dynamoql.ParseTimeAs(attr, dynamoql." + f.timeEncoding + ")"
+	}
+	return "dynamoql.Parse" + f.parser + "(attr)"
+}
+
+// notEmptyExpr boolean expression indicating the field f of receiver r holds a non-empty value.
+func notEmptyExpr(r string, f field) string {
+	switch f.goType {
+	case "bool":
+		return r + "." + f.name
+	case "time.Time":
+		return "!" + r + "." + f.name + ".IsZero()"
+	case "string", "dynamoql.Decimal":
+		return r + "." + f.name + ` != ""`
+	}
+	if strings.HasPrefix(f.goType, "[]") {
+		return "len(" + r + "." + f.name + ") > 0"
+	} else if strings.HasPrefix(f.goType, "*") {
+		return r + "." + f.name + " != nil"
+	}
+	return r + "." + f.name + " != 0"
+}
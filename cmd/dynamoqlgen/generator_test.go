@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name       string
+		typeNames  []string
+		importPath string
+		golden     string
+	}{
+		{
+			name:      "Node schema",
+			typeNames: []string{"Student"},
+			golden:    "student.golden",
+		},
+		{
+			name:      "Schema name from GetName",
+			typeNames: []string{"Classroom"},
+			golden:    "classroom.golden",
+		},
+		{
+			name:       "Edge schema",
+			typeNames:  []string{"StudentClassroom"},
+			importPath: defaultImportPath,
+			golden:     "student_classroom.golden",
+		},
+		{
+			name:      "Embedded struct",
+			typeNames: []string{"Embedded"},
+			golden:    "embedded.golden",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := generate(config{
+				dir:        filepath.Join("testdata", "model"),
+				typeNames:  tt.typeNames,
+				importPath: tt.importPath,
+				command:    "dynamoqlgen -type=" + tt.typeNames[0],
+			})
+			require.NoError(t, err)
+			golden := filepath.Join("testdata", tt.golden)
+			if *update {
+				require.NoError(t, os.WriteFile(golden, src, 0644))
+			}
+			exp, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(exp), string(src))
+		})
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeName string
+		exp      error
+	}{
+		{
+			name:     "Not found",
+			typeName: "Teacher",
+			exp:      errTypeNotFound,
+		},
+		{
+			name:     "Embedded pointer",
+			typeName: "EmbeddedPointer",
+			exp:      errUnsupportedEmbeds,
+		},
+		{
+			name:     "Tagged embedded struct",
+			typeName: "EmbeddedTagged",
+			exp:      errUnsupportedEmbeds,
+		},
+		{
+			name:     "Nested struct",
+			typeName: "Nested",
+			exp:      errUnsupportedType,
+		},
+		{
+			name:     "List",
+			typeName: "List",
+			exp:      errUnsupportedType,
+		},
+		{
+			name:     "Invalid composite",
+			typeName: "InvalidComposite",
+			exp:      errInvalidStructTag,
+		},
+		{
+			name:     "Unknown option",
+			typeName: "UnknownOption",
+			exp:      errInvalidStructTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generate(config{
+				dir:       filepath.Join("testdata", "model"),
+				typeNames: []string{tt.typeName},
+			})
+			assert.ErrorIs(t, err, tt.exp)
+		})
+	}
+}
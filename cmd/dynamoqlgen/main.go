@@ -0,0 +1,83 @@
+// Dynamoqlgen generates MarshalDynamoDB, UnmarshalDynamoDB, GetKeys and GetName methods for annotated struct types,
+// avoiding both reflection costs of dynamoql.Marshal and hand-written boilerplate.
+//
+// Struct fields are annotated using the same struct tags as dynamoql.Marshal (see dynamoql.StructTagName) plus the
+// key option, which marks attributes returned by GetKeys. GetName is generated only if the schema field holds a
+// schema name.
+//
+// e.g.
+//
+//	//go:generate dynamoqlgen -type=Student
+//	type Student struct {
+//		_           struct{} `dynamoql:"Student,schema"`
+//		StudentID   string   `dynamoql:"partition_key,composite=STUDENT,key"`
+//		ClassroomID string   `dynamoql:"sort_key,composite=CLASSROOM,key"`
+//		DisplayName string   `dynamoql:"display_name,omitempty"`
+//	}
+//
+// Supported field types are string, bool, integers, floats, []byte, time.Time, time.Duration and arbitrary-precision
+// numbers (dynamoql.Decimal, *big.Int, *big.Float and *big.Rat). Slices of strings, numbers and binaries are supported
+// using the set option. Untagged embedded structs declared in the same package are flattened as dynamoql.Marshal does,
+// other embedded fields are not supported.
+//
+// Usage:
+//
+//	dynamoqlgen [flags] -type T [directory]
+//
+// Methods are written into {type}_dynamoql.go (lowercase name of the first type) by default.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames  = flag.String("type", "", "comma-separated list of type names; must be set")
+	output     = flag.String("output", "", "output file name; default {dir}/{type}_dynamoql.go")
+	importPath = flag.String("import", "", "dynamoql import path; default is the path imported by the package")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of dynamoqlgen:\n")
+	fmt.Fprintf(os.Stderr, "\tdynamoqlgen [flags] -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("dynamoqlgen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	types := strings.Split(*typeNames, ",")
+	src, err := generate(config{
+		dir:        dir,
+		typeNames:  types,
+		importPath: *importPath,
+		command:    "dynamoqlgen " + strings.Join(os.Args[1:], " "),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(types[0])+"_dynamoql.go")
+	}
+	if err = os.WriteFile(outputName, src, 0644); err != nil {
+		log.Fatalf("writing output: %s", err)
+	}
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/maestre3d/dynamoql"
+)
+
+type Student struct {
+	_           struct{}         `dynamoql:"Student,schema"`
+	StudentID   string           `dynamoql:"partition_key,composite=STUDENT,key"`
+	SortKey     string           `dynamoql:"sort_key,key"`
+	DisplayName string           `dynamoql:"display_name,omitempty"`
+	Picture     []byte           `dynamoql:"picture,omitempty"`
+	Age, Grade  int              `dynamoql:",omitempty"`
+	Average     float64          `dynamoql:"average"`
+	Active      bool             `dynamoql:"active,omitempty"`
+	Tags        []string         `dynamoql:"tags,set"`
+	EnrolledAt  time.Time        `dynamoql:"enrolled_at,unixtime"`
+	UpdatedAt   time.Time        `dynamoql:"updated_at,omitempty"`
+	SeenAt      time.Time        `dynamoql:"seen_at,unixmilli"`
+	Timeout     time.Duration    `dynamoql:"timeout,omitempty"`
+	Balance     dynamoql.Decimal `dynamoql:"balance"`
+	Credits     *big.Int         `dynamoql:"credits,omitempty"`
+
+	Classrooms []Classroom `dynamoql:"-"`
+	internal   string
+}
+
+type Classroom struct {
+	_           struct{} `dynamoql:",schema"`
+	FacilityID  string   `dynamoql:"partition_key,composite=FACILITY,key"`
+	ClassroomID string   `dynamoql:"sort_key,composite=CLASSROOM,key"`
+	DisplayName string   `dynamoql:"display_name"`
+}
+
+func (c Classroom) GetName() string {
+	return "Classroom"
+}
+
+type StudentClassroom struct {
+	ClassroomID string    `dynamoql:"partition_key,composite=CLASSROOM,key"`
+	StudentID   string    `dynamoql:"sort_key,composite=STUDENT,key"`
+	AddedAt     time.Time `dynamoql:"added_time"`
+}
+
+type Audit struct {
+	CreatedAt time.Time `dynamoql:"created_at,unixtime"`
+	UpdatedBy string    `dynamoql:"updated_by,omitempty"`
+}
+
+type Embedded struct {
+	ID string `dynamoql:"id,key"`
+	Audit
+	audit
+}
+
+type audit struct {
+	Version int `dynamoql:"version"`
+}
+
+type EmbeddedPointer struct {
+	*Classroom
+}
+
+type EmbeddedTagged struct {
+	Audit `dynamoql:"audit"`
+}
+
+type Nested struct {
+	Student Student `dynamoql:"student"`
+}
+
+type List struct {
+	Tags []string `dynamoql:"tags"`
+}
+
+type InvalidComposite struct {
+	Count int `dynamoql:"count,composite=C"`
+}
+
+type UnknownOption struct {
+	Name string `dynamoql:"name,required"`
+}
+
+var _ = dynamoql.Marshal
@@ -1,13 +1,13 @@
 module example
 
-go 1.17
+go 1.18
 
 require (
 	github.com/aws/aws-sdk-go-v2 v1.16.5
 	github.com/aws/aws-sdk-go-v2/config v1.15.10
 	github.com/aws/aws-sdk-go-v2/credentials v1.12.5
 	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.15.4
-	github.com/maestre3d/dynamoql v0.0.1
+	github.com/maestre3d/dynamoql-go v0.0.1
 )
 
 require (
@@ -24,4 +24,4 @@ require (
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
 )
 
-replace github.com/maestre3d/dynamoql => ../..
+replace github.com/maestre3d/dynamoql-go => ../..
@@ -115,7 +115,7 @@ func main() {
 		StudentID: "123-abc",
 		InvoiceID: "1420",
 		AddedAt:   time.Now().UTC(),
-		Balance:   28458338.00,
+		Balance:   "28458338.00",
 		DueDate:   "10/31/25",
 		Status:    "Cancelled",
 	})
@@ -123,7 +123,7 @@ func main() {
 		StudentID: "123-abc",
 		InvoiceID: "1425",
 		AddedAt:   time.Now().UTC(),
-		Balance:   8458338.00,
+		Balance:   "8458338.00",
 		DueDate:   "12/31/25",
 		Status:    "Active",
 	})
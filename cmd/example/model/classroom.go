@@ -4,15 +4,16 @@ import (
 	"example/global"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-	"github.com/maestre3d/dynamoql"
+	dynamoql "github.com/maestre3d/dynamoql-go"
 )
 
 type Classroom struct {
-	FacilityID  string `json:"facility_id"`
-	ClassroomID string `json:"classroom_id"`
-	DisplayName string `json:"display_name"`
+	_           struct{} `dynamoql:",schema"`
+	FacilityID  string   `json:"facility_id" dynamoql:"partition_key,composite=FACILITY"`
+	ClassroomID string   `json:"classroom_id" dynamoql:"sort_key,composite=CLASSROOM"`
+	DisplayName string   `json:"display_name" dynamoql:"display_name"`
 
-	Students []Student `json:"students,omitempty"` // populated manually
+	Students []Student `json:"students,omitempty" dynamoql:"-"` // populated manually
 }
 
 var _ dynamoql.NodeSchema = &Classroom{}
@@ -29,17 +30,9 @@ func (c Classroom) GetKeys() map[string]types.AttributeValue {
 }
 
 func (c Classroom) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
-	return map[string]types.AttributeValue{
-		"partition_key":             dynamoql.FormatAttribute(dynamoql.NewCompositeKey(global.FacilityKeyName, c.FacilityID)),
-		"sort_key":                  dynamoql.FormatAttribute(dynamoql.NewCompositeKey(global.ClassroomKeyName, c.ClassroomID)),
-		"display_name":              dynamoql.FormatAttribute(c.DisplayName),
-		dynamoql.DefaultSchemaField: dynamoql.FormatAttribute(c.GetName()),
-	}, nil
+	return dynamoql.Marshal(c)
 }
 
 func (c *Classroom) UnmarshalDynamoDB(m map[string]types.AttributeValue) error {
-	c.FacilityID = dynamoql.ParseCompositeKey(dynamoql.MustParseString(m["partition_key"]))
-	c.ClassroomID = dynamoql.ParseCompositeKey(dynamoql.MustParseString(m["sort_key"]))
-	c.DisplayName = dynamoql.MustParseString(m["display_name"])
-	return nil
+	return dynamoql.Unmarshal(m, c)
 }
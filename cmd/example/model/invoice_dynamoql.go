@@ -0,0 +1,86 @@
+// Code generated by "dynamoqlgen -type=Invoice"; DO NOT EDIT.
+
+package model
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	dynamoql "github.com/maestre3d/dynamoql-go"
+)
+
+func (i Invoice) GetName() string {
+	return "Invoice"
+}
+
+func (i Invoice) GetKeys() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"partition_key": dynamoql.FormatAttribute(dynamoql.NewCompositeKey("STUDENT", i.StudentID)),
+		"sort_key":      dynamoql.FormatAttribute(dynamoql.NewCompositeKey("INVOICE", i.InvoiceID)),
+	}
+}
+
+func (i Invoice) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
+	item := map[string]types.AttributeValue{
+		"partition_key":             dynamoql.FormatAttribute(dynamoql.NewCompositeKey("STUDENT", i.StudentID)),
+		"sort_key":                  dynamoql.FormatAttribute(dynamoql.NewCompositeKey("INVOICE", i.InvoiceID)),
+		"added_at":                  dynamoql.FormatAttribute(i.AddedAt),
+		"invoice_due_date":          dynamoql.FormatAttribute(i.DueDate),
+		"invoice_status":            dynamoql.FormatAttribute(i.Status),
+		dynamoql.DefaultSchemaField: dynamoql.FormatAttribute(i.GetName()),
+	}
+	var err error
+	if item["invoice_balance"], err = dynamoql.MarshalAttribute(i.Balance); err != nil {
+		return nil, dynamoql.AttributeError{Attribute: "invoice_balance", Err: err}
+	}
+	return item, nil
+}
+
+func (i *Invoice) UnmarshalDynamoDB(item map[string]types.AttributeValue) error {
+	if attr, ok := item[dynamoql.DefaultSchemaField]; ok {
+		if name, _ := dynamoql.ParseString(attr); name != i.GetName() {
+			return dynamoql.AttributeError{Attribute: dynamoql.DefaultSchemaField, Err: dynamoql.ErrInvalidSchema}
+		}
+	}
+	if attr, ok := item["partition_key"]; ok {
+		val, err := dynamoql.ParseString(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "partition_key", Err: err}
+		}
+		i.StudentID = dynamoql.ParseCompositeKey(val)
+	}
+	if attr, ok := item["sort_key"]; ok {
+		val, err := dynamoql.ParseString(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "sort_key", Err: err}
+		}
+		i.InvoiceID = dynamoql.ParseCompositeKey(val)
+	}
+	if attr, ok := item["added_at"]; ok {
+		val, err := dynamoql.ParseTime(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "added_at", Err: err}
+		}
+		i.AddedAt = val
+	}
+	if attr, ok := item["invoice_balance"]; ok {
+		val, err := dynamoql.ParseDecimal(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "invoice_balance", Err: err}
+		}
+		i.Balance = val
+	}
+	if attr, ok := item["invoice_due_date"]; ok {
+		val, err := dynamoql.ParseString(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "invoice_due_date", Err: err}
+		}
+		i.DueDate = val
+	}
+	if attr, ok := item["invoice_status"]; ok {
+		val, err := dynamoql.ParseString(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "invoice_status", Err: err}
+		}
+		i.Status = val
+	}
+	return nil
+}
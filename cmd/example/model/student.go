@@ -6,7 +6,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-	"github.com/maestre3d/dynamoql"
+	dynamoql "github.com/maestre3d/dynamoql-go"
 )
 
 type Student struct {
@@ -42,13 +42,14 @@ func (b Student) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
 }
 
 func (b *Student) UnmarshalDynamoDB(v map[string]types.AttributeValue) error {
-	if schema := dynamoql.MustParseString(v[dynamoql.DefaultSchemaField]); schema != b.GetName() {
+	d := dynamoql.NewItemDecoder(v).Require("partition_key")
+	if schema := d.String(dynamoql.DefaultSchemaField); schema != b.GetName() {
 		return errors.New("invalid schema")
 	}
-	b.StudentID = dynamoql.ParseCompositeKey(dynamoql.MustParseString(v["partition_key"]))
-	b.DisplayName = dynamoql.MustParseString(v["display_name"])
-	b.Picture = dynamoql.MustParseString(v["picture"])
-	return nil
+	b.StudentID = dynamoql.ParseCompositeKey(d.String("partition_key"))
+	b.DisplayName = d.String("display_name")
+	b.Picture = d.String("picture")
+	return d.Err()
 }
 
 // GSI Overloading will do the heavy-lifting for us as it will project most of the data automatically.
@@ -57,11 +58,13 @@ func (b *Student) UnmarshalDynamoDB(v map[string]types.AttributeValue) error {
 // Moreover, if we want to fetch all classroom's students, we use default table (no GSI).
 
 // StudentClassroom Many to many Student - Classroom.
+//
+//go:generate go run github.com/maestre3d/dynamoql-go/cmd/dynamoqlgen -type=StudentClassroom
 type StudentClassroom struct {
-	ClassroomID string
-	StudentID   string
-	FacilityID  string // required to hydrate a posteriori
-	AddedAt     time.Time
+	ClassroomID string    `dynamoql:"partition_key,composite=CLASSROOM,key"`
+	StudentID   string    `dynamoql:"sort_key,composite=STUDENT,key"`
+	FacilityID  string    `dynamoql:"facility_id,composite=FACILITY"` // required to hydrate a posteriori
+	AddedAt     time.Time `dynamoql:"added_time"`
 }
 
 var _ dynamoql.EdgeSchema = &StudentClassroom{}
@@ -75,30 +78,6 @@ func (b StudentClassroom) GetLeftKeys() map[string]types.AttributeValue {
 
 func (b StudentClassroom) GetRightKeys() map[string]types.AttributeValue {
 	return Student{
-		StudentID: b.FacilityID,
+		StudentID: b.StudentID,
 	}.GetKeys()
 }
-
-func (b StudentClassroom) GetKeys() map[string]types.AttributeValue {
-	return map[string]types.AttributeValue{
-		"partition_key": dynamoql.FormatAttribute(dynamoql.NewCompositeKey(global.ClassroomKeyName, b.ClassroomID)),
-		"sort_key":      dynamoql.FormatAttribute(dynamoql.NewCompositeKey(global.StudentKeyName, b.StudentID)),
-	}
-}
-
-func (b StudentClassroom) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
-	return map[string]types.AttributeValue{
-		"partition_key": dynamoql.FormatAttribute(dynamoql.NewCompositeKey(global.ClassroomKeyName, b.ClassroomID)),
-		"sort_key":      dynamoql.FormatAttribute(dynamoql.NewCompositeKey(global.StudentKeyName, b.StudentID)),
-		"facility_id":   dynamoql.FormatAttribute(dynamoql.NewCompositeKey(global.FacilityKeyName, b.FacilityID)),
-		"added_time":    dynamoql.FormatAttribute(b.AddedAt),
-	}, nil
-}
-
-func (b *StudentClassroom) UnmarshalDynamoDB(v map[string]types.AttributeValue) error {
-	b.ClassroomID = dynamoql.ParseCompositeKey(dynamoql.MustParseString(v["partition_key"]))
-	b.StudentID = dynamoql.ParseCompositeKey(dynamoql.MustParseString(v["sort_key"]))
-	b.FacilityID = dynamoql.ParseCompositeKey(dynamoql.MustParseString(v["facility_id"]))
-	b.AddedAt = dynamoql.MustParseTime(v["added_time"])
-	return nil
-}
@@ -0,0 +1,56 @@
+// Code generated by "dynamoqlgen -type=StudentClassroom"; DO NOT EDIT.
+
+package model
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	dynamoql "github.com/maestre3d/dynamoql-go"
+)
+
+func (s StudentClassroom) GetKeys() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"partition_key": dynamoql.FormatAttribute(dynamoql.NewCompositeKey("CLASSROOM", s.ClassroomID)),
+		"sort_key":      dynamoql.FormatAttribute(dynamoql.NewCompositeKey("STUDENT", s.StudentID)),
+	}
+}
+
+func (s StudentClassroom) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
+	return map[string]types.AttributeValue{
+		"partition_key": dynamoql.FormatAttribute(dynamoql.NewCompositeKey("CLASSROOM", s.ClassroomID)),
+		"sort_key":      dynamoql.FormatAttribute(dynamoql.NewCompositeKey("STUDENT", s.StudentID)),
+		"facility_id":   dynamoql.FormatAttribute(dynamoql.NewCompositeKey("FACILITY", s.FacilityID)),
+		"added_time":    dynamoql.FormatAttribute(s.AddedAt),
+	}, nil
+}
+
+func (s *StudentClassroom) UnmarshalDynamoDB(item map[string]types.AttributeValue) error {
+	if attr, ok := item["partition_key"]; ok {
+		val, err := dynamoql.ParseString(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "partition_key", Err: err}
+		}
+		s.ClassroomID = dynamoql.ParseCompositeKey(val)
+	}
+	if attr, ok := item["sort_key"]; ok {
+		val, err := dynamoql.ParseString(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "sort_key", Err: err}
+		}
+		s.StudentID = dynamoql.ParseCompositeKey(val)
+	}
+	if attr, ok := item["facility_id"]; ok {
+		val, err := dynamoql.ParseString(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "facility_id", Err: err}
+		}
+		s.FacilityID = dynamoql.ParseCompositeKey(val)
+	}
+	if attr, ok := item["added_time"]; ok {
+		val, err := dynamoql.ParseTime(attr)
+		if err != nil {
+			return dynamoql.AttributeError{Attribute: "added_time", Err: err}
+		}
+		s.AddedAt = val
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamoql "github.com/maestre3d/dynamoql-go"
 )
 
 func SaveInvoice(ctx context.Context, c *dynamodb.Client, m model.Invoice) error {
@@ -20,3 +21,14 @@ func SaveInvoice(ctx context.Context, c *dynamodb.Client, m model.Invoice) error
 	})
 	return err
 }
+
+func UpdateInvoiceStatus(ctx context.Context, c *dynamodb.Client, m model.Invoice) error {
+	_, err := dynamoql.Update(m.GetKeys()).Table(global.TableName).
+		Set("invoice_status", m.Status).
+		Where(dynamoql.Condition{
+			Operator: dynamoql.AttributeExists,
+			Field:    "partition_key",
+		}).
+		ExecUpdate(ctx, c)
+	return err
+}
@@ -7,8 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-	"github.com/maestre3d/dynamoql"
+	dynamoql "github.com/maestre3d/dynamoql-go"
 )
 
 func GetStudent(ctx context.Context, c *dynamodb.Client, student *model.Student) error {
@@ -31,12 +30,16 @@ func GetStudent(ctx context.Context, c *dynamodb.Client, student *model.Student)
 }
 
 func GetStudentHydrate(ctx context.Context, c *dynamodb.Client, student *model.Student) error {
-	// 1. Get relationships (Get all classrooms assigned to a student).
+	if err := GetStudent(ctx, c, student); err != nil {
+		return err
+	}
+
+	// 1. Hydrate relationships (Get all classrooms assigned to a student).
 	//
 	// Note: Base table uses classroom_id as PK and student_id as SK.
 	// Hence, the usage of the overloaded GSI is required.
-	// N-M relation
-	outQuery, err := dynamoql.Select().From(global.TableName).
+	// N-M relation, edges are paginated and classrooms are fetched using Batch API for each page
+	edges, err := dynamoql.HydrateEdges(dynamoql.Select().From(global.TableName).
 		Where(dynamoql.Condition{
 			IsKey:    true,
 			Operator: dynamoql.Equals,
@@ -49,12 +52,21 @@ func GetStudentHydrate(ctx context.Context, c *dynamodb.Client, student *model.S
 			Value:    dynamoql.NewCompositeKey(global.ClassroomKeyName, ""),
 		}).
 		Index(global.GsiName).
-		Limit(100). // DO NOT hydrate many schemas as Batch and Query APIs have byte size limitations of response
-		ExecQuery(ctx, c)
+		Limit(100), // DO NOT hydrate many schemas per page as Batch and Query APIs have byte size limitations
+		func() dynamoql.EdgeSchema { return &model.StudentClassroom{} },
+		func() dynamoql.NodeSchema { return &model.Classroom{} }).
+		Side(dynamoql.EdgeLeft).
+		ExecHydrate(ctx, c)
 	if err != nil {
 		return err
 	}
-	// 1-N relation
+	for _, edge := range edges {
+		if classroom, ok := edge.Node.(*model.Classroom); ok {
+			student.Classrooms = append(student.Classrooms, *classroom)
+		}
+	}
+
+	// 2. 1-N relation
 	outQueryInv, err := dynamoql.Select().From(global.TableName).
 		Where(dynamoql.Condition{
 			IsKey:    true,
@@ -80,52 +92,6 @@ func GetStudentHydrate(ctx context.Context, c *dynamodb.Client, student *model.S
 		}
 		student.Invoices = append(student.Invoices, marshal)
 	}
-
-	// 2. Build key slice out of items to hydrate using Batch API
-	bufKeys := make([]map[string]types.AttributeValue, 0, 1+outQuery.Count)
-	bufKeys = append(bufKeys, student.GetKeys())
-
-	for _, item := range outQuery.Items {
-		marshal := model.StudentClassroom{}
-		err = marshal.UnmarshalDynamoDB(item)
-		if err != nil {
-			continue
-		}
-		bufKeys = append(bufKeys, marshal.GetLeftKeys())
-	}
-
-	// 3. Perform the actual fetching
-	out, err := c.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
-		RequestItems: map[string]types.KeysAndAttributes{
-			global.TableName: {
-				Keys:                     bufKeys,
-				AttributesToGet:          nil,
-				ConsistentRead:           nil,
-				ExpressionAttributeNames: nil,
-				ProjectionExpression:     nil,
-			},
-		},
-		ReturnConsumedCapacity: "",
-	})
-	if err != nil {
-		return err
-	}
-
-	// 4. Unmarshal data, requires a way to determine which model is going to be decoded.
-	for _, item := range out.Responses[global.TableName] {
-		if dynamoql.MustParseString(item[dynamoql.DefaultSchemaField]) == "Student" {
-			if err = student.UnmarshalDynamoDB(item); err == nil {
-				continue
-			}
-		}
-		classroom := model.Classroom{}
-		err = classroom.UnmarshalDynamoDB(item)
-		if err != nil {
-			continue
-		}
-		student.Classrooms = append(student.Classrooms, classroom)
-	}
-
 	return nil
 }
 
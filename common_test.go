@@ -74,3 +74,19 @@ func newDynamoLocalResolver() dynamodb.EndpointResolverFunc {
 		}, nil
 	}
 }
+
+func mustNewQueryInput(q *dynamoql.QueryBuilder) dynamodb.QueryInput {
+	in, err := dynamoql.NewQueryInput(q)
+	if err != nil {
+		panic(err)
+	}
+	return in
+}
+
+func mustNewScanInput(q *dynamoql.QueryBuilder) dynamodb.ScanInput {
+	in, err := dynamoql.NewScanInput(q)
+	if err != nil {
+		panic(err)
+	}
+	return in
+}
@@ -0,0 +1,43 @@
+package dynamoql
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrConditionalCheckFailed the condition expression of a write operation (Put, Update or Delete) evaluated to false.
+//
+// Errors matching ErrConditionalCheckFailed also wrap the original *types.ConditionalCheckFailedException.
+var ErrConditionalCheckFailed = errors.New("dynamoql: Conditional check failed")
+
+// conditionalCheckError an Amazon DynamoDB conditional check failure matching ErrConditionalCheckFailed.
+type conditionalCheckError struct {
+	cause error
+}
+
+var _ error = conditionalCheckError{}
+
+func (e conditionalCheckError) Error() string {
+	return ErrConditionalCheckFailed.Error() + ": " + e.cause.Error()
+}
+
+// Is indicates if target is ErrConditionalCheckFailed.
+func (e conditionalCheckError) Is(target error) bool {
+	return target == ErrConditionalCheckFailed
+}
+
+// Unwrap retrieves the original Amazon DynamoDB error.
+func (e conditionalCheckError) Unwrap() error {
+	return e.cause
+}
+
+// newWriteError maps Amazon DynamoDB conditional check failures to ErrConditionalCheckFailed. Any other error is
+// returned as is.
+func newWriteError(err error) error {
+	var errCheck *types.ConditionalCheckFailedException
+	if errors.As(err, &errCheck) {
+		return conditionalCheckError{cause: err}
+	}
+	return err
+}
@@ -0,0 +1,22 @@
+package dynamoql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWriteError(t *testing.T) {
+	errCheck := &types.ConditionalCheckFailedException{Message: new(string)}
+	err := newWriteError(fmt.Errorf("operation error: %w", errCheck))
+	assert.ErrorIs(t, err, ErrConditionalCheckFailed)
+	var errOrigin *types.ConditionalCheckFailedException
+	assert.True(t, errors.As(err, &errOrigin))
+
+	errGeneric := errors.New("generic error")
+	assert.Equal(t, errGeneric, newWriteError(errGeneric))
+	assert.NotErrorIs(t, newWriteError(errGeneric), ErrConditionalCheckFailed)
+}
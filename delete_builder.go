@@ -0,0 +1,152 @@
+package dynamoql
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DeleteBuilder crafts an Amazon DynamoDB delete statement ready to be used by DeleteItem API.
+//
+// Conditions (Where) are written as the condition expression of the delete, hence the item is deleted only if it
+// matches them.
+//
+// e.g. dynamoql.Delete(student.GetKeys()).Table("Graph")
+type DeleteBuilder struct {
+	negate        bool
+	operator      LogicalOperator
+	table         string
+	keys          map[string]types.AttributeValue
+	conditions    []Condition
+	returnValues  types.ReturnValue
+	returnMetrics types.ReturnConsumedCapacity
+}
+
+// NewDeleteBuilder builds a DeleteBuilder instance.
+func NewDeleteBuilder() *DeleteBuilder {
+	return &DeleteBuilder{
+		returnValues:  types.ReturnValueNone,
+		returnMetrics: types.ReturnConsumedCapacityNone,
+	}
+}
+
+// Delete builds a new DeleteBuilder instance and sets the primary key of the item to be deleted.
+func Delete(keys map[string]types.AttributeValue) *DeleteBuilder {
+	return NewDeleteBuilder().Keys(keys)
+}
+
+// Keys sets the primary key (partition and sort keys) of the item to be deleted.
+func (d *DeleteBuilder) Keys(keys map[string]types.AttributeValue) *DeleteBuilder {
+	d.keys = keys
+	return d
+}
+
+// Table sets the table to delete the item from.
+func (d *DeleteBuilder) Table(table string) *DeleteBuilder {
+	d.table = table
+	return d
+}
+
+// Where sets conditions statements. The item is deleted only if it matches them.
+func (d *DeleteBuilder) Where(c ...Condition) *DeleteBuilder {
+	d.conditions = c
+	return d
+}
+
+// And concatenates Condition(s) with an And operator.
+func (d *DeleteBuilder) And() *DeleteBuilder {
+	d.operator = And
+	return d
+}
+
+// Or concatenates Condition(s) with an Or operator.
+func (d *DeleteBuilder) Or() *DeleteBuilder {
+	d.operator = Or
+	return d
+}
+
+// Negate sets the condition statements output to be opposite.
+func (d *DeleteBuilder) Negate() *DeleteBuilder {
+	d.negate = true
+	return d
+}
+
+// ReturnValues sets the item attributes to be returned by the delete operation. Accepts either
+// types.ReturnValueNone or types.ReturnValueAllOld.
+func (d *DeleteBuilder) ReturnValues(v types.ReturnValue) *DeleteBuilder {
+	d.returnValues = v
+	return d
+}
+
+// Metrics sets the desired metric data from consumed capacity outputs.
+func (d *DeleteBuilder) Metrics(v types.ReturnConsumedCapacity) *DeleteBuilder {
+	d.returnMetrics = v
+	return d
+}
+
+// Validate verifies the current DeleteBuilder instance values are accepted by DeleteItem API, returning a
+// ValidationError if not.
+func (d *DeleteBuilder) Validate() error {
+	if d.table == "" {
+		return newValidationError(ErrMissingTable, "", "table name is required (Table)")
+	} else if len(d.keys) == 0 {
+		return newValidationError(ErrMissingKeys, "", "item primary key is required (Keys)")
+	} else if err := validateWriteReturnValues(d.returnValues); err != nil {
+		return err
+	}
+	return validateWriteConditions(d.operator, d.conditions)
+}
+
+// NewDeleteInput builds a dynamodb.DeleteItemInput using current DeleteBuilder instance values.
+//
+// Returns a ValidationError if DeleteBuilder values are not accepted by DeleteItem API.
+func NewDeleteInput(d *DeleteBuilder) (dynamodb.DeleteItemInput, error) {
+	if err := d.Validate(); err != nil {
+		return dynamodb.DeleteItemInput{}, err
+	}
+	a := newPlaceholderAllocator()
+	conditionExpr, err := buildExpression(a, d.operator, d.negate, d.conditions)
+	if err != nil {
+		return dynamodb.DeleteItemInput{}, err
+	}
+	return dynamodb.DeleteItemInput{
+		Key:                       d.keys,
+		TableName:                 &d.table,
+		ConditionExpression:       conditionExpr,
+		ExpressionAttributeNames:  a.attributeNames(),
+		ExpressionAttributeValues: a.attributeValues(),
+		ReturnConsumedCapacity:    d.returnMetrics,
+		ReturnValues:              d.returnValues,
+	}, nil
+}
+
+// ExecDelete executes a DeleteItem API operation.
+//
+// Returns a ValidationError if DeleteBuilder values are not accepted by DeleteItem API and ErrConditionalCheckFailed
+// if the item does not match the conditions.
+func (d *DeleteBuilder) ExecDelete(ctx context.Context, c *dynamodb.Client) (dynamodb.DeleteItemOutput, error) {
+	in, err := NewDeleteInput(d)
+	if err != nil {
+		return dynamodb.DeleteItemOutput{}, err
+	}
+	out, err := c.DeleteItem(ctx, &in)
+	if err != nil {
+		return dynamodb.DeleteItemOutput{}, newWriteError(err)
+	}
+	return *out, nil
+}
+
+// Statement builds a PartiQL DELETE Statement using current DeleteBuilder instance values.
+//
+// e.g. DELETE FROM "Graph" WHERE "partition_key" = ? AND "sort_key" = ? AND "status" = ?
+func (d *DeleteBuilder) Statement() (Statement, error) {
+	if err := d.Validate(); err != nil {
+		return Statement{}, err
+	}
+	w := statementWriter{}
+	w.buf.WriteString("DELETE FROM ")
+	w.writeTable(d.table, nil)
+	w.writeItemWhere(d.keys, d.operator, d.negate, d.conditions)
+	return w.statement()
+}
@@ -0,0 +1,92 @@
+package dynamoql_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeleteInput(t *testing.T) {
+	keys := Bill{InvoiceID: "1191", BillID: "1"}.GetKeys()
+	in, err := dynamoql.NewDeleteInput(dynamoql.Delete(keys).
+		Table("InvoiceAndBills").
+		Negate().
+		Where(dynamoql.Condition{
+			Operator: dynamoql.Equals,
+			Field:    "status",
+			Value:    "PAID",
+		}).
+		ReturnValues(types.ReturnValueAllOld))
+	require.NoError(t, err)
+	assert.Equal(t, keys, in.Key)
+	assert.Equal(t, "InvoiceAndBills", *in.TableName)
+	assert.Equal(t, "NOT (#n0 = :v0)", *in.ConditionExpression)
+	assert.Equal(t, map[string]string{"#n0": "status"}, in.ExpressionAttributeNames)
+	assert.Equal(t, map[string]types.AttributeValue{
+		":v0": &types.AttributeValueMemberS{Value: "PAID"},
+	}, in.ExpressionAttributeValues)
+	assert.Equal(t, types.ReturnValueAllOld, in.ReturnValues)
+	assert.Equal(t, types.ReturnConsumedCapacityNone, in.ReturnConsumedCapacity)
+}
+
+func TestDeleteBuilder_Validate(t *testing.T) {
+	keys := Bill{InvoiceID: "1191", BillID: "1"}.GetKeys()
+	tests := []struct {
+		name  string
+		query *dynamoql.DeleteBuilder
+		exp   error
+	}{
+		{
+			name:  "Missing table",
+			query: dynamoql.Delete(keys),
+			exp:   dynamoql.ErrMissingTable,
+		},
+		{
+			name:  "Missing keys",
+			query: dynamoql.Delete(nil).Table("sample"),
+			exp:   dynamoql.ErrMissingKeys,
+		},
+		{
+			name:  "Invalid return values",
+			query: dynamoql.Delete(keys).Table("sample").ReturnValues(types.ReturnValueAllNew),
+			exp:   dynamoql.ErrInvalidOption,
+		},
+		{
+			name:  "Invalid condition",
+			query: dynamoql.Delete(keys).Table("sample").Where(dynamoql.Condition{}, dynamoql.Condition{}),
+			exp:   dynamoql.ErrInvalidCondition,
+		},
+		{
+			name:  "Valid",
+			query: dynamoql.Delete(keys).Table("sample"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.query.Validate(), tt.exp)
+		})
+	}
+}
+
+func TestDeleteBuilder_Statement(t *testing.T) {
+	s, err := dynamoql.Delete(Bill{InvoiceID: "1191", BillID: "1"}.GetKeys()).
+		Table("InvoiceAndBills").
+		Or().
+		Where(dynamoql.Condition{
+			Operator: dynamoql.Equals,
+			Field:    "status",
+			Value:    "PAID",
+		}, dynamoql.Condition{
+			Operator: dynamoql.AttributeNotExists,
+			Field:    "status",
+		}).
+		Statement()
+	require.NoError(t, err)
+	assert.Equal(t, `DELETE FROM "InvoiceAndBills" WHERE "PK" = ? AND "SK" = ? AND `+
+		`("status" = ? OR "status" IS MISSING)`, s.Query)
+	assert.Equal(t, `DELETE FROM "InvoiceAndBills" WHERE "PK" = 'I#1191' AND "SK" = 'B#1' AND `+
+		`("status" = 'PAID' OR "status" IS MISSING)`, s.String())
+}
@@ -0,0 +1,162 @@
+package dynamoql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	documentPathSeparator  = '.'
+	documentPathIndexStart = '['
+	documentPathIndexEnd   = ']'
+
+	// documentPathPrefix marks a field as document path (see Path). Attribute names are not expected to start with a
+	// NUL character.
+	documentPathPrefix = "\x00"
+)
+
+// Path marks the given document path (e.g. address.city, items[2].sku or matrix[0][1]) to be split into its
+// elements, each one allocated as a separate attribute name. Accepted by Condition fields, FieldRef operands,
+// projected fields and update fields.
+//
+// Fields not marked by Path are always handled as a single attribute name, even if they contain dots or brackets
+// (e.g. an attribute named "address.city").
+func Path(p string) string {
+	if strings.HasPrefix(p, documentPathPrefix) {
+		return p
+	}
+	return documentPathPrefix + p
+}
+
+// fieldName retrieves the given field without the Path mark.
+func fieldName(field string) string {
+	return strings.TrimPrefix(field, documentPathPrefix)
+}
+
+// fieldElements splits the given field into its document path elements. Fields not marked by Path, as well as
+// invalid document paths, are handled as a single attribute name.
+func fieldElements(field string) []documentPathElement {
+	p := fieldName(field)
+	if len(p) == len(field) {
+		return []documentPathElement{{name: field}}
+	} else if elems, ok := parseDocumentPath(p); ok {
+		return elems
+	}
+	return []documentPathElement{{name: p}}
+}
+
+// documentPathElement a single element of a document path. Either an attribute name or a list index.
+type documentPathElement struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// parseDocumentPath splits the given document path into its elements.
+//
+// A document path is composed by attribute names separated by dots (maps) and list indexes enclosed within brackets
+// (e.g. address.city, items[2].sku or matrix[0][1]).
+//
+// Returns false if p is not a valid document path.
+//
+// See ref: https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/Expressions.Attributes.html#Expressions.Attributes.NestedElements.DocumentPathExamples
+func parseDocumentPath(p string) ([]documentPathElement, bool) {
+	if p == "" {
+		return nil, false
+	}
+	buf := make([]documentPathElement, 0, strings.Count(p, ".")+strings.Count(p, "[")+1)
+	start := 0
+	expectName := true
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case documentPathSeparator:
+			if expectName {
+				if i == start {
+					return nil, false
+				}
+				buf = append(buf, documentPathElement{name: p[start:i]})
+			}
+			start = i + 1
+			expectName = true
+		case documentPathIndexStart:
+			if expectName {
+				if i == start {
+					return nil, false
+				}
+				buf = append(buf, documentPathElement{name: p[start:i]})
+			}
+			end := strings.IndexByte(p[i:], documentPathIndexEnd)
+			if end < 0 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(p[i+1 : i+end])
+			if err != nil || index < 0 {
+				return nil, false
+			}
+			buf = append(buf, documentPathElement{index: index, isIndex: true})
+			i += end
+			start = i + 1
+			expectName = false
+		case documentPathIndexEnd:
+			return nil, false
+		default:
+			if !expectName {
+				// a list index MUST be followed by either a separator or another list index
+				return nil, false
+			}
+		}
+	}
+	if expectName {
+		if start == len(p) {
+			return nil, false
+		}
+		buf = append(buf, documentPathElement{name: p[start:]})
+	}
+	return buf, true
+}
+
+// path retrieves the expression token of the given field, allocating a token for each attribute name element of
+// document paths marked by Path (e.g. Path("address.city") => #n0.#n1, Path("items[2].sku") => #n2[2].#n3).
+//
+// Fields not marked by Path are handled as a single attribute name (e.g. address.city => #n4).
+func (a *placeholderAllocator) path(field string) string {
+	elems := fieldElements(field)
+	buf := strings.Builder{}
+	for i := range elems {
+		if elems[i].isIndex {
+			buf.WriteByte(documentPathIndexStart)
+			buf.WriteString(strconv.Itoa(elems[i].index))
+			buf.WriteByte(documentPathIndexEnd)
+			continue
+		}
+		if i > 0 {
+			buf.WriteByte(documentPathSeparator)
+		}
+		buf.WriteString(a.name(elems[i].name))
+	}
+	return buf.String()
+}
+
+// buildProjectionExpression crafts an Amazon DynamoDB projection expression from the given attribute names or
+// document paths (Path), allocating name placeholders using a.
+func buildProjectionExpression(a *placeholderAllocator, fields []string) *string {
+	if len(fields) == 0 {
+		return nil
+	}
+	buf := strings.Builder{}
+	for i := range fields {
+		if fields[i] == "" {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(a.path(fields[i]))
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	return aws.String(buf.String())
+}
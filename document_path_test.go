@@ -0,0 +1,145 @@
+package dynamoql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentPath(t *testing.T) {
+	tests := []struct {
+		Name  string
+		In    string
+		Exp   []documentPathElement
+		Valid bool
+	}{
+		{
+			Name: "Empty",
+			In:   "",
+		},
+		{
+			Name: "Attribute name",
+			In:   "foo",
+			Exp: []documentPathElement{
+				{name: "foo"},
+			},
+			Valid: true,
+		},
+		{
+			Name: "Map",
+			In:   "address.city",
+			Exp: []documentPathElement{
+				{name: "address"},
+				{name: "city"},
+			},
+			Valid: true,
+		},
+		{
+			Name: "List",
+			In:   "items[2].sku",
+			Exp: []documentPathElement{
+				{name: "items"},
+				{index: 2, isIndex: true},
+				{name: "sku"},
+			},
+			Valid: true,
+		},
+		{
+			Name: "Nested lists",
+			In:   "matrix[0][11]",
+			Exp: []documentPathElement{
+				{name: "matrix"},
+				{index: 0, isIndex: true},
+				{index: 11, isIndex: true},
+			},
+			Valid: true,
+		},
+		{
+			Name: "Special characters",
+			In:   "foo-bar.baz qux",
+			Exp: []documentPathElement{
+				{name: "foo-bar"},
+				{name: "baz qux"},
+			},
+			Valid: true,
+		},
+		{
+			Name: "Trailing separator",
+			In:   "foo.",
+		},
+		{
+			Name: "Leading separator",
+			In:   ".foo",
+		},
+		{
+			Name: "Double separator",
+			In:   "foo..bar",
+		},
+		{
+			Name: "Missing list name",
+			In:   "[0]",
+		},
+		{
+			Name: "Unclosed index",
+			In:   "foo[0",
+		},
+		{
+			Name: "Invalid index",
+			In:   "foo[bar]",
+		},
+		{
+			Name: "Negative index",
+			In:   "foo[-1]",
+		},
+		{
+			Name: "Missing separator after index",
+			In:   "foo[0]bar",
+		},
+		{
+			Name: "Unopened index",
+			In:   "foo0]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			out, ok := parseDocumentPath(tt.In)
+			require.Equal(t, tt.Valid, ok)
+			assert.Equal(t, tt.Exp, out)
+		})
+	}
+}
+
+func TestPlaceholderAllocatorPath(t *testing.T) {
+	a := newPlaceholderAllocator()
+	assert.Equal(t, "#n0", a.path("foo"))
+	assert.Equal(t, "#n1.#n2", a.path(Path("address.city")))
+	assert.Equal(t, "#n3[2].#n4", a.path(Path("items[2].sku")))
+	assert.Equal(t, "#n1.#n5[0][1]", a.path(Path(Path("address.matrix[0][1]"))))
+	assert.Equal(t, "#n6", a.path(Path("foo[bar]"))) // invalid paths are handled as attribute names
+	// fields not marked as document paths are handled as attribute names
+	assert.Equal(t, "#n7", a.path("address.city"))
+	assert.Equal(t, "#n8", a.path("items[2].sku"))
+	assert.Equal(t, map[string]string{
+		"#n0": "foo",
+		"#n1": "address",
+		"#n2": "city",
+		"#n3": "items",
+		"#n4": "sku",
+		"#n5": "matrix",
+		"#n6": "foo[bar]",
+		"#n7": "address.city",
+		"#n8": "items[2].sku",
+	}, a.attributeNames())
+}
+
+func TestBuildProjectionExpression(t *testing.T) {
+	a := newPlaceholderAllocator()
+	assert.Nil(t, buildProjectionExpression(a, nil))
+	assert.Nil(t, buildProjectionExpression(a, []string{""}))
+	out := buildProjectionExpression(a, []string{"name", Path("address.city"), "", Path("items[0].sku"), "name",
+		"address.city"})
+	require.NotNil(t, out)
+	assert.Equal(t, "#n0,#n1.#n2,#n3[0].#n4,#n0,#n5", *out)
+	assert.Equal(t, "address.city", a.attributeNames()["#n5"])
+}
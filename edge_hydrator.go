@@ -0,0 +1,162 @@
+package dynamoql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// ErrMissingSchemaFactory an EdgeHydrator requires both edge and node factories.
+var ErrMissingSchemaFactory = errors.New("dynamoql: Missing schema factory")
+
+// EdgeSide side of an EdgeSchema relation to be hydrated.
+type EdgeSide string
+
+const (
+	// EdgeLeft hydrates nodes using EdgeSchema.GetLeftKeys.
+	EdgeLeft EdgeSide = "LEFT"
+	// EdgeRight hydrates nodes using EdgeSchema.GetRightKeys.
+	EdgeRight EdgeSide = "RIGHT"
+)
+
+// EdgeFactory allocates an empty EdgeSchema ready to decode an edge item. MUST return a pointer as decoding requires
+// modifying the schema (e.g. &StudentClassroom{}).
+type EdgeFactory func() EdgeSchema
+
+// HydratedEdge a node paired with the edge relating it.
+type HydratedEdge struct {
+	// Edge the decoded edge item, holds relation attributes.
+	Edge EdgeSchema
+	// Node the decoded node item. Nil if the node was not found.
+	Node NodeSchema
+}
+
+// EdgeHydrator resolves the nodes of an Amazon DynamoDB Many-To-Many relation (EdgeSchema).
+//
+// Edges are fetched using the Query API, either from the base table or an inverted index (GSI overloading), and
+// paginated until every edge is fetched. The query Limit is used as page size. For each page, node keys from the
+// hydrated side (left or right) are retrieved using a BatchGetBuilder.
+//
+// e.g.
+//
+//	edges, err := dynamoql.HydrateEdges(q,
+//		func() dynamoql.EdgeSchema { return &StudentClassroom{} },
+//		func() dynamoql.NodeSchema { return &Classroom{} }).
+//		Side(dynamoql.EdgeLeft).
+//		ExecHydrate(ctx, c)
+type EdgeHydrator struct {
+	query       *QueryBuilder
+	edge        EdgeFactory
+	node        SchemaFactory
+	side        EdgeSide
+	table       string
+	concurrency int
+}
+
+// HydrateEdges builds an EdgeHydrator instance. Edges are fetched using q and decoded with edge factory while nodes
+// are decoded with node factory. Left side nodes are hydrated by default.
+func HydrateEdges(q *QueryBuilder, edge EdgeFactory, node SchemaFactory) *EdgeHydrator {
+	return &EdgeHydrator{
+		query:       q,
+		edge:        edge,
+		node:        node,
+		side:        EdgeLeft,
+		concurrency: 1,
+	}
+}
+
+// Side sets the side of the relation to be hydrated.
+func (h *EdgeHydrator) Side(s EdgeSide) *EdgeHydrator {
+	h.side = s
+	return h
+}
+
+// Table sets the table to retrieve nodes from. Defaults to the table of the edge query.
+func (h *EdgeHydrator) Table(table string) *EdgeHydrator {
+	h.table = table
+	return h
+}
+
+// Concurrency sets the maximum number of BatchGetItem API requests running concurrently for each page of edges.
+// Defaults to 1.
+func (h *EdgeHydrator) Concurrency(n int) *EdgeHydrator {
+	h.concurrency = n
+	return h
+}
+
+// Validate verifies the current EdgeHydrator instance values, returning a ValidationError if not valid.
+func (h *EdgeHydrator) Validate() error {
+	if h.query == nil {
+		return newValidationError(ErrMissingQuery, "", "edge query is required")
+	} else if h.edge == nil || h.node == nil {
+		return newValidationError(ErrMissingSchemaFactory, "", "edge and node factories are required")
+	} else if h.side != EdgeLeft && h.side != EdgeRight {
+		return newValidationError(ErrInvalidOption, "", "edge side must be either LEFT or RIGHT")
+	} else if h.concurrency <= 0 {
+		return newValidationError(ErrInvalidOption, "", "concurrency must be greater than zero")
+	}
+	return nil
+}
+
+// ExecHydrate fetches every edge and its node, keeping edges order.
+//
+// Returns a ValidationError if either EdgeHydrator or edge query values are not valid.
+func (h *EdgeHydrator) ExecHydrate(ctx context.Context, c *dynamodb.Client) ([]HydratedEdge, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	p, err := h.query.GetQueryPaginator(c)
+	if err != nil {
+		return nil, err
+	}
+	return h.hydrate(ctx, p, func(ctx context.Context, in *dynamodb.BatchGetItemInput) (
+		*dynamodb.BatchGetItemOutput, error) {
+		return c.BatchGetItem(ctx, in)
+	})
+}
+
+func (h *EdgeHydrator) hydrate(ctx context.Context, p ItemPaginator, fn batchGetFunc) ([]HydratedEdge, error) {
+	table := h.table
+	if table == "" {
+		table = h.query.table
+	}
+	buf := make([]HydratedEdge, 0)
+	for p.Next() {
+		items, err := p.GetItems(ctx)
+		if err != nil {
+			return nil, err
+		} else if len(items) == 0 {
+			continue
+		}
+		batch := NewBatchGetBuilder().Concurrency(h.concurrency)
+		offset := len(buf)
+		for _, item := range items {
+			edge := h.edge()
+			if err = edge.UnmarshalDynamoDB(item); err != nil {
+				return nil, err
+			}
+			buf = append(buf, HydratedEdge{Edge: edge})
+			if h.side == EdgeRight {
+				batch.Keys(table, edge.GetRightKeys())
+				continue
+			}
+			batch.Keys(table, edge.GetLeftKeys())
+		}
+		out, err := batch.execBatchGet(ctx, fn)
+		if err != nil {
+			return nil, err
+		}
+		for i, item := range out.Items {
+			if item == nil {
+				continue
+			}
+			node := h.node()
+			if err = node.UnmarshalDynamoDB(item); err != nil {
+				return nil, err
+			}
+			buf[offset+i].Node = node
+		}
+	}
+	return buf, nil
+}
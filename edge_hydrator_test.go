@@ -0,0 +1,158 @@
+package dynamoql
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hydratorTestNode struct {
+	ID   string
+	Name string
+}
+
+func (n hydratorTestNode) GetName() string {
+	return "Node"
+}
+
+func (n hydratorTestNode) GetKeys() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": FormatAttribute(n.ID),
+		"SK": FormatAttribute(n.ID),
+	}
+}
+
+func (n hydratorTestNode) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
+	return map[string]types.AttributeValue{
+		"PK":   FormatAttribute(n.ID),
+		"SK":   FormatAttribute(n.ID),
+		"name": FormatAttribute(n.Name),
+	}, nil
+}
+
+func (n *hydratorTestNode) UnmarshalDynamoDB(m map[string]types.AttributeValue) error {
+	n.ID = MustParseString(m["PK"])
+	n.Name = MustParseString(m["name"])
+	return nil
+}
+
+type hydratorTestEdge struct {
+	LeftID  string
+	RightID string
+	Role    string
+}
+
+func (e hydratorTestEdge) GetLeftKeys() map[string]types.AttributeValue {
+	return hydratorTestNode{ID: e.LeftID}.GetKeys()
+}
+
+func (e hydratorTestEdge) GetRightKeys() map[string]types.AttributeValue {
+	return hydratorTestNode{ID: e.RightID}.GetKeys()
+}
+
+func (e hydratorTestEdge) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
+	return map[string]types.AttributeValue{
+		"PK":   FormatAttribute(e.LeftID),
+		"SK":   FormatAttribute(e.RightID),
+		"role": FormatAttribute(e.Role),
+	}, nil
+}
+
+func (e *hydratorTestEdge) UnmarshalDynamoDB(m map[string]types.AttributeValue) error {
+	e.LeftID = MustParseString(m["PK"])
+	e.RightID = MustParseString(m["SK"])
+	e.Role = MustParseString(m["role"])
+	return nil
+}
+
+type hydratorTestPaginator struct {
+	pages [][]map[string]types.AttributeValue
+}
+
+func (p *hydratorTestPaginator) Next() bool {
+	return len(p.pages) > 0
+}
+
+func (p *hydratorTestPaginator) NextPageToken() PageToken {
+	return nil
+}
+
+func (p *hydratorTestPaginator) GetItems(_ context.Context) ([]map[string]types.AttributeValue, error) {
+	page := p.pages[0]
+	p.pages = p.pages[1:]
+	return page, nil
+}
+
+func TestEdgeHydrator(t *testing.T) {
+	edges := make([]map[string]types.AttributeValue, 0, 150)
+	for i := 0; i < 150; i++ {
+		edge, _ := hydratorTestEdge{
+			LeftID:  "CLASSROOM#" + strconv.Itoa(i%60),
+			RightID: "STUDENT#1",
+			Role:    "MEMBER",
+		}.MarshalDynamoDB()
+		edges = append(edges, edge)
+	}
+	var requestedTables []string
+	batchGet := func(_ context.Context, in *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+		out := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{}}
+		for table, req := range in.RequestItems {
+			requestedTables = append(requestedTables, table)
+			for _, key := range req.Keys {
+				id := MustParseString(key["PK"])
+				if id == "CLASSROOM#7" {
+					continue
+				}
+				node, _ := hydratorTestNode{ID: id, Name: "Name of " + id}.MarshalDynamoDB()
+				out.Responses[table] = append(out.Responses[table], node)
+			}
+		}
+		return out, nil
+	}
+
+	h := HydrateEdges(Select().From("Graph").Index("GsiOverload"),
+		func() EdgeSchema { return &hydratorTestEdge{} },
+		func() NodeSchema { return &hydratorTestNode{} })
+	require.NoError(t, h.Validate())
+	out, err := h.hydrate(context.Background(), &hydratorTestPaginator{
+		pages: [][]map[string]types.AttributeValue{edges[:120], {}, edges[120:]},
+	}, batchGet)
+	require.NoError(t, err)
+	require.Len(t, out, 150)
+	assert.Equal(t, []string{"Graph", "Graph"}, requestedTables)
+	for i := range out {
+		edge := out[i].Edge.(*hydratorTestEdge)
+		assert.Equal(t, "MEMBER", edge.Role)
+		if edge.LeftID == "CLASSROOM#7" {
+			assert.Nil(t, out[i].Node)
+			continue
+		}
+		require.NotNil(t, out[i].Node)
+		assert.Equal(t, "Name of "+edge.LeftID, out[i].Node.(*hydratorTestNode).Name)
+	}
+
+	requestedTables = nil
+	out, err = h.Side(EdgeRight).Table("Students").hydrate(context.Background(), &hydratorTestPaginator{
+		pages: [][]map[string]types.AttributeValue{edges[:2]},
+	}, batchGet)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Students"}, requestedTables)
+	assert.Equal(t, &hydratorTestNode{ID: "STUDENT#1", Name: "Name of STUDENT#1"}, out[0].Node)
+	assert.Equal(t, out[0].Node, out[1].Node)
+}
+
+func TestEdgeHydrator_Validate(t *testing.T) {
+	edge := func() EdgeSchema { return &hydratorTestEdge{} }
+	node := func() NodeSchema { return &hydratorTestNode{} }
+	assert.ErrorIs(t, HydrateEdges(nil, edge, node).Validate(), ErrMissingQuery)
+	assert.ErrorIs(t, HydrateEdges(Select(), nil, node).Validate(), ErrMissingSchemaFactory)
+	assert.ErrorIs(t, HydrateEdges(Select(), edge, node).Side("TOP").Validate(), ErrInvalidOption)
+	assert.ErrorIs(t, HydrateEdges(Select(), edge, node).Concurrency(0).Validate(), ErrInvalidOption)
+	_, err := HydrateEdges(Select().From("Graph"), edge, node).ExecHydrate(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrMissingKeyCondition)
+}
@@ -1,18 +1,12 @@
 package dynamoql
 
 import (
-	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
-const (
-	expressionNameSeparator  = "#"
-	expressionValueSeparator = ":"
-)
-
 // buildExpressionValuesRaw crafts a ExpressionValue using raw key names (does not requires ExpressionNames).
 func buildExpressionValuesRaw(c []Condition) map[string]types.AttributeValue {
 	if c == nil || len(c) == 0 {
@@ -29,38 +23,59 @@ func buildExpressionValuesRaw(c []Condition) map[string]types.AttributeValue {
 
 // expression Amazon DynamoDB payload to execute specified filters and queries.
 type expression struct {
-	Names            map[string]string
-	Values           map[string]types.AttributeValue
-	KeyExpression    *string
-	FilterExpression *string
+	Names                map[string]string
+	Values               map[string]types.AttributeValue
+	KeyExpression        *string
+	FilterExpression     *string
+	ProjectionExpression *string
 }
 
 // expressionBuilder crafts a DynamoDB expression.
 type expressionBuilder struct {
-	negate              bool
-	operator            LogicalOperator
-	conditions          []Condition
-	conditionsKeys      []Condition
-	conditionsAttr      []Condition
-	expressionNamesBuf  map[string]string
-	expressionValuesBuf map[string]types.AttributeValue
-	expressionKey       *string
-	expressionFilter    *string
+	negate               bool
+	operator             LogicalOperator
+	conditions           []Condition
+	conditionsKeys       []Condition
+	conditionsAttr       []Condition
+	projectedFields      []string
+	placeholders         *placeholderAllocator
+	expressionKey        *string
+	expressionFilter     *string
+	expressionProjection *string
 }
 
 // newExpression builds an expression from the given data.
-func newExpression(op LogicalOperator, negate bool, c []Condition) expression {
+//
+// Returns the MarshalAttribute error of the first condition value which cannot be converted into an Amazon DynamoDB
+// attribute.
+func newExpression(op LogicalOperator, negate bool, c []Condition, projectedFields []string) (expression, error) {
 	b := &expressionBuilder{
-		negate:     negate,
-		operator:   op,
-		conditions: c,
+		negate:          negate,
+		operator:        op,
+		conditions:      c,
+		projectedFields: projectedFields,
+		placeholders:    newPlaceholderAllocator(),
 	}
 	return b.build()
 }
 
+// flattenConditions retrieves the leaf Condition(s) of the given condition tree, discarding groups.
+func flattenConditions(c []Condition) []Condition {
+	buf := make([]Condition, 0, len(c))
+	for i := range c {
+		if c[i].Group == nil {
+			buf = append(buf, c[i])
+			continue
+		}
+		buf = append(buf, flattenConditions(c[i].Group.Conditions)...)
+	}
+	return buf
+}
+
 // splitConditions separates Condition Keys and Condition Attributes.
 //
-// ConditionKeys are used by Amazon DynamoDB to execute queries related to Partition and Sort keys.
+// ConditionKeys are used by Amazon DynamoDB to execute queries related to Partition and Sort keys. Key groups are
+// flattened as key expressions only accept And operators.
 //
 // AttributeKeys are used by Amazon DynamoDB to execute queries related to non-primary key fields.
 func (b *expressionBuilder) splitConditions() {
@@ -68,56 +83,118 @@ func (b *expressionBuilder) splitConditions() {
 		return
 	}
 	for i := range b.conditions {
-		if b.conditions[i].IsKey {
+		if !b.conditions[i].IsKey {
+			b.conditionsAttr = append(b.conditionsAttr, b.conditions[i])
+			continue
+		} else if b.conditions[i].Group == nil {
 			b.conditionsKeys = append(b.conditionsKeys, b.conditions[i])
 			continue
 		}
-		b.conditionsAttr = append(b.conditionsAttr, b.conditions[i])
+		for _, c := range flattenConditions(b.conditions[i].Group.Conditions) {
+			c.IsKey = true
+			b.conditionsKeys = append(b.conditionsKeys, c)
+		}
 	}
 }
 
-// buildNames maps field names from an Amazon DynamoDB table to a custom token used by expressions.
+// expressionNode a Condition (or ConditionGroup) with its operand placeholders already allocated, ready to be written
+// into an expression.
+type expressionNode struct {
+	negate   bool
+	isGroup  bool
+	operator LogicalOperator
+	nodes    []expressionNode
+	args     expressionBuilderFuncArgs
+}
+
+// compileConditions allocates placeholders for each operand of the given condition tree.
 //
-// Default format is key => #key.
-func (b *expressionBuilder) buildNames() {
-	if b.conditions == nil || len(b.conditions) == 0 {
-		return
-	}
-	b.expressionNamesBuf = make(map[string]string, len(b.conditions))
-	for i := range b.conditions {
-		b.expressionNamesBuf[expressionNameSeparator+b.conditions[i].Field] =
-			b.conditions[i].Field
+// Empty groups and conditions without a field are discarded as they cannot be expressed.
+func compileConditions(a *placeholderAllocator, c []Condition) ([]expressionNode, error) {
+	nodes := make([]expressionNode, 0, len(c))
+	for i := range c {
+		if c[i].Group != nil {
+			group, err := compileConditions(a, c[i].Group.Conditions)
+			if err != nil {
+				return nil, err
+			} else if len(group) == 0 {
+				continue
+			}
+			nodes = append(nodes, expressionNode{
+				negate:   c[i].Negate,
+				isGroup:  true,
+				operator: newLogicalOperator(c[i].Group.Operator),
+				nodes:    group,
+			})
+			continue
+		} else if c[i].Field == "" {
+			continue
+		}
+		name := a.path(c[i].Field)
+		values, err := allocateValues(a, c[i])
+		if err != nil {
+			return nil, AttributeError{Attribute: fieldName(c[i].Field), Err: err}
+		}
+		nodes = append(nodes, expressionNode{
+			negate: !c[i].IsKey && c[i].Negate,
+			args: expressionBuilderFuncArgs{
+				name:              name,
+				values:            values,
+				operator:          c[i].Operator,
+				secondaryOperator: c[i].SecondaryOperator,
+			},
+		})
 	}
+	return nodes, nil
 }
 
-// buildValues maps values of an Amazon DynamoDB expression to a custom token.
-//
-// Default format is key => :key
+// allocateValues allocates a placeholder for each right-hand operand required by the Condition operator.
 //
-// If more than one key, then => :key, :key0, :key1, :keyN ...
-func (b *expressionBuilder) buildValues() {
-	if b.conditions == nil || len(b.conditions) == 0 {
-		return
+// Operands might be either attribute values or attribute names (FieldRef).
+func allocateValues(a *placeholderAllocator, c Condition) ([]string, error) {
+	op := c.Operator
+	if op == Size {
+		// size() function operands depend on the comparison operator
+		op = c.SecondaryOperator
 	}
-	b.expressionValuesBuf = make(map[string]types.AttributeValue, len(b.conditions))
-	for i := range b.conditions {
-		key := expressionValueSeparator + b.conditions[i].Field
-		b.expressionValuesBuf[key] = FormatAttribute(b.conditions[i].Value)
-		if b.conditions[i].ExtraValues == nil || len(b.conditions[i].ExtraValues) == 0 {
-			continue
+	var operands []interface{}
+	switch op {
+	case AttributeExists, AttributeNotExists:
+		return nil, nil
+	case In:
+		operands = append(make([]interface{}, 0, 1+len(c.ExtraValues)), c.Value)
+		operands = append(operands, c.ExtraValues...)
+	case Between:
+		operands = []interface{}{c.Value}
+		if len(c.ExtraValues) > 0 {
+			operands = append(operands, c.ExtraValues[0])
 		}
-		// Only applies to IN operator
-		// O(mn) - Time complex. where m = extra values & n = total conditions.
-		for j, attr := range b.conditions[i].ExtraValues {
-			b.expressionValuesBuf[key+strconv.Itoa(j)] = FormatAttribute(attr)
+	default:
+		operands = []interface{}{c.Value}
+	}
+	buf := make([]string, 0, len(operands))
+	for _, v := range operands {
+		tok, err := a.operand(v)
+		if err != nil {
+			return nil, err
 		}
+		buf = append(buf, tok)
 	}
+	return buf, nil
 }
 
 type expressionBuilderFuncArgs struct {
-	field                       string
+	name                        string
+	values                      []string
 	operator, secondaryOperator ConditionalOperator
-	totalExtraVal               int
+}
+
+// value retrieves the value placeholder at the given position. Returns an empty string if not found.
+func (a expressionBuilderFuncArgs) value(n int) string {
+	if n >= len(a.values) {
+		return ""
+	}
+	return a.values[n]
 }
 
 // expressionBuilderFunc based on the given arguments, it will build an Amazon DynamoDB expression (key or filter).
@@ -144,85 +221,79 @@ func newExpressionBuilderFunc(o ConditionalOperator) expressionBuilderFunc {
 }
 
 func buildBetweenExpression(buf *strings.Builder, args expressionBuilderFuncArgs) {
-	buf.WriteString(expressionNameSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.name)
 	buf.WriteByte(' ')
 	buf.WriteString(string(Between))
 	buf.WriteByte(' ')
-	buf.WriteString(expressionValueSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.value(0))
 	buf.WriteByte(' ')
 	buf.WriteString(string(And))
 	buf.WriteByte(' ')
-	buf.WriteString(expressionValueSeparator)
-	buf.WriteString(args.field)
-	buf.WriteByte('0')
+	buf.WriteString(args.value(1))
 }
 
 func buildInExpression(buf *strings.Builder, args expressionBuilderFuncArgs) {
-	key := expressionValueSeparator + args.field
-	buf.WriteString(expressionNameSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.name)
 	buf.WriteByte(' ')
 	buf.WriteString(string(In))
 	buf.WriteByte(' ')
 	buf.WriteByte('(')
-	buf.WriteString(key)
-	if args.totalExtraVal > 0 {
-		buf.WriteByte(',')
-		for i := 0; i < args.totalExtraVal; i++ {
-			buf.WriteString(key + strconv.Itoa(i))
-			if args.totalExtraVal-1 != i {
-				buf.WriteByte(',')
-			}
+	for i := range args.values {
+		if i > 0 {
+			buf.WriteByte(',')
 		}
+		buf.WriteString(args.values[i])
 	}
 	buf.WriteByte(')')
 }
 
+// buildSizeExpression writes a size() function comparison. The function result is compared using the secondary
+// operator, which accepts comparators, Between and In operators (e.g. size(#n0) BETWEEN :v0 AND :v1).
 func buildSizeExpression(buf *strings.Builder, args expressionBuilderFuncArgs) {
 	buf.WriteString(string(Size))
 	buf.WriteByte('(')
-	buf.WriteString(expressionNameSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.name)
 	buf.WriteByte(')')
+	switch args.secondaryOperator {
+	case Between, In:
+		// reuses operator expression builders with size() function as left-hand operand
+		newExpressionBuilderFunc(args.secondaryOperator)(buf, expressionBuilderFuncArgs{
+			values:   args.values,
+			operator: args.secondaryOperator,
+		})
+		return
+	}
 	buf.WriteByte(' ')
 	buf.WriteString(string(args.secondaryOperator))
 	buf.WriteByte(' ')
-	buf.WriteString(expressionValueSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.value(0))
 }
 
 func buildFuncExpression(buf *strings.Builder, args expressionBuilderFuncArgs) {
 	buf.WriteString(string(args.operator))
 	buf.WriteByte('(')
-	buf.WriteString(expressionNameSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.name)
 	buf.WriteByte(',')
-	buf.WriteString(expressionValueSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.value(0))
 	buf.WriteByte(')')
 }
 
 func buildAttrExpression(buf *strings.Builder, args expressionBuilderFuncArgs) {
 	buf.WriteString(string(args.operator))
 	buf.WriteByte('(')
-	buf.WriteString(expressionNameSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.name)
 	buf.WriteByte(')')
 }
 
 func buildDefaultExpression(buf *strings.Builder, args expressionBuilderFuncArgs) {
-	if args.field == "" {
+	if args.name == "" {
 		return
 	}
-	buf.WriteString(expressionNameSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.name)
 	buf.WriteByte(' ')
 	buf.WriteString(string(args.operator))
 	buf.WriteByte(' ')
-	buf.WriteString(expressionValueSeparator)
-	buf.WriteString(args.field)
+	buf.WriteString(args.value(0))
 }
 
 // calculateExpressionFuncCap calculates the required capacity for strings.Builder's internal bytes buffer.
@@ -236,52 +307,54 @@ func calculateExpressionFuncCap(o ConditionalOperator, args expressionBuilderFun
 	totalExtraChars := 0
 	switch o {
 	case Between:
-		totalExtraChars = 5
-		return totalExtraChars + len(expressionNameSeparator) + (len(expressionValueSeparator) * 2) +
-			len(Between) + len(And) + (len(args.field) * 3)
+		totalExtraChars = 4
+		return totalExtraChars + len(args.name) + len(Between) + len(And) + len(args.value(0)) + len(args.value(1))
 	case In:
-		key := expressionValueSeparator + args.field
+		// represents ' IN ()' and commas between values
 		totalExtraChars = 4
-
-		requiredCap := totalExtraChars + len(expressionNameSeparator) + len(expressionValueSeparator) +
-			len(In) + (len(args.field) * 2)
-		if args.totalExtraVal > 0 {
-			totalExtraChars = 2
-			if args.totalExtraVal > 10 {
-				totalExtraChars++
-			}
-			requiredCap += (len(key) + totalExtraChars) * args.totalExtraVal
-			if args.totalExtraVal > 10 {
-				requiredCap -= 10 // first 10 values do not contain double-digit, remove
-			}
+		if len(args.values) > 1 {
+			totalExtraChars += len(args.values) - 1
 		}
-		return requiredCap
+		return totalExtraChars + len(args.name) + len(In) + sumLen(args.values)
 	case Size:
+		switch args.secondaryOperator {
+		case Between, In:
+			// represents 'size()' followed by the operator expression without left-hand operand
+			totalExtraChars = 2
+			return totalExtraChars + len(Size) + len(args.name) +
+				calculateExpressionFuncCap(args.secondaryOperator, expressionBuilderFuncArgs{values: args.values})
+		}
 		totalExtraChars = 4
-		return totalExtraChars + len(expressionNameSeparator) + len(expressionValueSeparator) +
-			len(Size) + (len(args.field) * 2) + len(args.secondaryOperator)
+		return totalExtraChars + len(Size) + len(args.name) + len(args.secondaryOperator) + len(args.value(0))
 	case AttributeExists, AttributeNotExists:
 		totalExtraChars = 2
-		return totalExtraChars + len(expressionNameSeparator) +
-			len(args.operator) + len(args.field)
+		return totalExtraChars + len(args.operator) + len(args.name)
 	case BeginsWith, Contains, AttributeType:
 		totalExtraChars = 3
-		return totalExtraChars + len(expressionNameSeparator) + len(expressionValueSeparator) +
-			len(args.operator) + (len(args.field) * 2)
+		return totalExtraChars + len(args.operator) + len(args.name) + len(args.value(0))
 	default:
-		if args.field == "" {
+		if args.name == "" {
 			return 0
 		}
 		totalExtraChars = 2
-		return totalExtraChars + len(expressionNameSeparator) + len(expressionValueSeparator) +
-			len(args.operator) + (len(args.field) * 2)
+		return totalExtraChars + len(args.operator) + len(args.name) + len(args.value(0))
+	}
+}
+
+// sumLen calculates the total length of the given strings.
+func sumLen(s []string) int {
+	total := 0
+	for i := range s {
+		total += len(s[i])
 	}
+	return total
 }
 
-func calculateExpressionBufferCap(operator LogicalOperator, negate bool, c []Condition) int {
+func calculateExpressionBufferCap(operator LogicalOperator, negate bool, nodes []expressionNode) int {
 	const whiteSpaces = 2
+	operator = newLogicalOperator(operator)
 	bufSize := 0
-	if len(c) > 1 {
+	if len(nodes) > 1 {
 		// f(x) outputs total bytes concatenated for each logical operator.
 		// (e.g. #foo = :foo AND #bar >= :bar => f(x) = 5)
 		// (e.g. #foo = :foo OR #bar >= :bar OR #baz contains(:bar) => f(x) = 8)
@@ -289,86 +362,134 @@ func calculateExpressionBufferCap(operator LogicalOperator, negate bool, c []Con
 		// Ol = Logical Operator Length (either AND or operator OR)
 		// W = Total Whitespaces
 		// f(x) = Tc(W+Ol) - (Ol+W)
-		bufSize = len(c)*(whiteSpaces+len(operator)) - (len(operator) + whiteSpaces)
+		bufSize = len(nodes)*(whiteSpaces+len(operator)) - (len(operator) + whiteSpaces)
 	}
 	if negate {
 		// represents 'NOT ()'
 		bufSize += 6
 	}
-	for i := range c {
-		if !c[i].IsKey && c[i].Negate {
+	for i := range nodes {
+		if nodes[i].isGroup {
+			// represents '()', negation is written without extra parentheses (i.e. 'NOT ()')
+			bufSize += 2
+			if nodes[i].negate {
+				bufSize += 4
+			}
+			bufSize += calculateExpressionBufferCap(nodes[i].operator, false, nodes[i].nodes)
+			continue
+		}
+		if nodes[i].negate {
 			bufSize += 6
 		}
-		bufSize += calculateExpressionFuncCap(c[i].Operator, expressionBuilderFuncArgs{
-			field:             c[i].Field,
-			operator:          c[i].Operator,
-			secondaryOperator: c[i].SecondaryOperator,
-			totalExtraVal:     len(c[i].ExtraValues),
-		})
+		bufSize += calculateExpressionFuncCap(nodes[i].args.operator, nodes[i].args)
 	}
 	return bufSize
 }
 
-func buildExpression(operator LogicalOperator, negate bool, c []Condition) *string {
+// newLogicalOperator returns the given LogicalOperator or And if empty.
+func newLogicalOperator(op LogicalOperator) LogicalOperator {
+	if op == "" {
+		return And
+	}
+	return op
+}
+
+// buildExpression crafts an Amazon DynamoDB expression from the given condition tree, allocating operand
+// placeholders using a.
+//
+// Returns an AttributeError if a condition value cannot be converted into an Amazon DynamoDB attribute.
+func buildExpression(a *placeholderAllocator, operator LogicalOperator, negate bool, c []Condition) (*string,
+	error) {
 	if len(c) == 0 {
-		return nil
+		return nil, nil
+	}
+	nodes, err := compileConditions(a, c)
+	if err != nil || len(nodes) == 0 {
+		return nil, err
 	}
 	// took reference from:
 	// https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/Expressions.OperatorsAndFunctions.html
 	buf := strings.Builder{}
-	buf.Grow(calculateExpressionBufferCap(operator, negate, c))
+	buf.Grow(calculateExpressionBufferCap(operator, negate, nodes))
+	writeExpression(&buf, operator, negate, nodes)
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+	return aws.String(buf.String()), nil
+}
+
+// writeExpression writes the given nodes into buf, concatenating each node with operator.
+//
+// Nested groups are enclosed within parentheses.
+func writeExpression(buf *strings.Builder, operator LogicalOperator, negate bool, nodes []expressionNode) {
+	operator = newLogicalOperator(operator)
 	if negate {
 		buf.WriteString(string(not))
 		buf.WriteString(" (")
 	}
-	for i := range c {
-		if !c[i].IsKey && c[i].Negate {
-			buf.WriteString(string(not))
-			buf.WriteString(" (")
-		}
-		newExpressionBuilderFunc(c[i].Operator)(&buf, expressionBuilderFuncArgs{
-			field:             c[i].Field,
-			operator:          c[i].Operator,
-			secondaryOperator: c[i].SecondaryOperator,
-			totalExtraVal:     len(c[i].ExtraValues),
-		})
-		if !c[i].IsKey && c[i].Negate {
-			buf.WriteByte(')')
-		}
-		if len(c)-1 > i {
+	for i := range nodes {
+		if i > 0 {
 			buf.WriteByte(' ')
 			buf.WriteString(string(operator))
 			buf.WriteByte(' ')
 		}
+		writeExpressionNode(buf, nodes[i])
 	}
 	if negate {
 		buf.WriteByte(')')
 	}
-	if buf.Len() == 0 {
-		return nil
+}
+
+// writeExpressionNode writes a single expressionNode (or group) into buf.
+func writeExpressionNode(buf *strings.Builder, n expressionNode) {
+	if n.isGroup {
+		if n.negate {
+			buf.WriteString(string(not))
+			buf.WriteByte(' ')
+		}
+		buf.WriteByte('(')
+		writeExpression(buf, n.operator, false, n.nodes)
+		buf.WriteByte(')')
+		return
+	}
+	if n.negate {
+		buf.WriteString(string(not))
+		buf.WriteString(" (")
+	}
+	newExpressionBuilderFunc(n.args.operator)(buf, n.args)
+	if n.negate {
+		buf.WriteByte(')')
 	}
-	return aws.String(buf.String())
 }
 
-func (b *expressionBuilder) buildKeys() {
+func (b *expressionBuilder) buildKeys() (err error) {
 	// Key expressions DO NOT accept any LogicalOperator except And
-	b.expressionKey = buildExpression(And, false, b.conditionsKeys)
+	b.expressionKey, err = buildExpression(b.placeholders, And, false, b.conditionsKeys)
+	return err
+}
+
+func (b *expressionBuilder) buildFilters() (err error) {
+	b.expressionFilter, err = buildExpression(b.placeholders, b.operator, b.negate, b.conditionsAttr)
+	return err
 }
 
-func (b *expressionBuilder) buildFilters() {
-	b.expressionFilter = buildExpression(b.operator, b.negate, b.conditionsAttr)
+func (b *expressionBuilder) buildProjection() {
+	b.expressionProjection = buildProjectionExpression(b.placeholders, b.projectedFields)
 }
 
-func (b *expressionBuilder) build() expression {
-	b.buildNames()
-	b.buildValues()
+func (b *expressionBuilder) build() (expression, error) {
 	b.splitConditions()
-	b.buildKeys()
-	b.buildFilters()
-	return expression{
-		Names:            b.expressionNamesBuf,
-		Values:           b.expressionValuesBuf,
-		KeyExpression:    b.expressionKey,
-		FilterExpression: b.expressionFilter,
+	if err := b.buildKeys(); err != nil {
+		return expression{}, err
+	} else if err = b.buildFilters(); err != nil {
+		return expression{}, err
 	}
+	b.buildProjection()
+	return expression{
+		Names:                b.placeholders.attributeNames(),
+		Values:               b.placeholders.attributeValues(),
+		KeyExpression:        b.expressionKey,
+		FilterExpression:     b.expressionFilter,
+		ProjectionExpression: b.expressionProjection,
+	}, nil
 }
@@ -277,85 +277,110 @@ func TestNewExpressionBuilderFunc(t *testing.T) {
 			Name:     "Default",
 			Operator: GreaterOrEqualThan,
 			Args: expressionBuilderFuncArgs{
-				field: "foo",
+				name:   "#n0",
+				values: []string{":v0"},
 			},
-			Exp: "#foo >= :foo",
+			Exp: "#n0 >= :v0",
 		},
 		{
 			Name:     "Between",
 			Operator: Between,
 			Args: expressionBuilderFuncArgs{
-				field: "foo",
+				name:   "#n0",
+				values: []string{":v0", ":v1"},
 			},
-			Exp: "#foo BETWEEN :foo AND :foo0",
+			Exp: "#n0 BETWEEN :v0 AND :v1",
 		},
 		{
 			Name:     "In",
 			Operator: In,
 			Args: expressionBuilderFuncArgs{
-				field:         "foo",
-				totalExtraVal: 3,
+				name:   "#n0",
+				values: []string{":v0", ":v1", ":v2", ":v3"},
 			},
-			Exp: "#foo IN (:foo,:foo0,:foo1,:foo2)",
+			Exp: "#n0 IN (:v0,:v1,:v2,:v3)",
 		},
 		{
 			Name:     "In no extra values",
 			Operator: In,
 			Args: expressionBuilderFuncArgs{
-				field:         "foo",
-				totalExtraVal: 0,
+				name:   "#n0",
+				values: []string{":v0"},
 			},
-			Exp: "#foo IN (:foo)",
+			Exp: "#n0 IN (:v0)",
 		},
 		{
 			Name:     "Attribute not exists",
 			Operator: AttributeNotExists,
 			Args: expressionBuilderFuncArgs{
-				field: "foo",
+				name: "#n0",
 			},
-			Exp: "attribute_not_exists(#foo)",
+			Exp: "attribute_not_exists(#n0)",
 		},
 		{
 			Name:     "Attribute exists",
 			Operator: AttributeExists,
 			Args: expressionBuilderFuncArgs{
-				field: "foo",
+				name: "#n0",
 			},
-			Exp: "attribute_exists(#foo)",
+			Exp: "attribute_exists(#n0)",
 		},
 		{
 			Name:     "Function begins_with",
 			Operator: BeginsWith,
 			Args: expressionBuilderFuncArgs{
-				field: "foo",
+				name:   "#n0",
+				values: []string{":v0"},
 			},
-			Exp: "begins_with(#foo,:foo)",
+			Exp: "begins_with(#n0,:v0)",
 		},
 		{
 			Name:     "Function contains",
 			Operator: Contains,
 			Args: expressionBuilderFuncArgs{
-				field:         "foo",
-				totalExtraVal: 10,
+				name:   "#n0",
+				values: []string{":v0"},
 			},
-			Exp: "contains(#foo,:foo)",
+			Exp: "contains(#n0,:v0)",
 		},
 		{
 			Name:     "Function attribute_type",
 			Operator: AttributeType,
 			Args: expressionBuilderFuncArgs{
-				field: "foo",
+				name:   "#n0",
+				values: []string{":v0"},
 			},
-			Exp: "attribute_type(#foo,:foo)",
+			Exp: "attribute_type(#n0,:v0)",
 		},
 		{
 			Name:     "Function size",
 			Operator: Size,
 			Args: expressionBuilderFuncArgs{
-				field:             "foo",
+				name:              "#n0",
+				values:            []string{":v0"},
 				secondaryOperator: LessOrEqualThan,
 			},
-			Exp: "size(#foo) <= :foo",
+			Exp: "size(#n0) <= :v0",
+		},
+		{
+			Name:     "Function size between",
+			Operator: Size,
+			Args: expressionBuilderFuncArgs{
+				name:              "#n0",
+				values:            []string{":v0", ":v1"},
+				secondaryOperator: Between,
+			},
+			Exp: "size(#n0) BETWEEN :v0 AND :v1",
+		},
+		{
+			Name:     "Function size in",
+			Operator: Size,
+			Args: expressionBuilderFuncArgs{
+				name:              "#n0",
+				values:            []string{":v0", ":v1", ":v2"},
+				secondaryOperator: In,
+			},
+			Exp: "size(#n0) IN (:v0,:v1,:v2)",
 		},
 	}
 
@@ -365,6 +390,7 @@ func TestNewExpressionBuilderFunc(t *testing.T) {
 			tt.Args.operator = tt.Operator
 			newExpressionBuilderFunc(tt.Operator)(&buf, tt.Args)
 			assert.Equal(t, tt.Exp, buf.String())
+			assert.Equal(t, len(tt.Exp), calculateExpressionFuncCap(tt.Operator, tt.Args))
 		})
 	}
 }
@@ -373,11 +399,17 @@ func BenchmarkNewExpressionBuilderFunc(b *testing.B) {
 	// No longer allocates buffer capacity before writes.
 	// Pre-allocation is done in BuildExpression().
 	rootOp := In
+	values := make([]string, 0, 100) // max values accepted by IN operator
+	a := newPlaceholderAllocator()
+	for i := 0; i < cap(values); i++ {
+		v, _ := a.value(i)
+		values = append(values, v)
+	}
 	args := expressionBuilderFuncArgs{
-		field:             "foo",
+		name:              a.name("foo"),
+		values:            values,
 		operator:          rootOp,
 		secondaryOperator: "",
-		totalExtraVal:     100, // max values accepted by IN operator
 	}
 	for i := 0; i < b.N; i++ {
 		buf := strings.Builder{}
@@ -434,10 +466,372 @@ func TestBuildExpression(t *testing.T) {
 			ExtraValues:       nil,
 		},
 	}
-	out := buildExpression(And, false, conditions)
+	a := newPlaceholderAllocator()
+	out, err := buildExpression(a, And, false, conditions)
+	require.NoError(t, err)
 	require.NotNil(t, out)
-	exp := "NOT (#foo IN (:foo,:foo0,:foo1,:foo2,:foo3)) AND NOT (#foo IN (:foo,:foo0,:foo1,:foo2,:foo3,:foo4)) AND #foo = :foo AND contains(#foo,:foo) AND size(#foo) >= :foo"
+	exp := "NOT (#n0 IN (:v0,:v1,:v2,:v3,:v4)) AND NOT (#n0 IN (:v5,:v6,:v7,:v8,:v9,:v10)) AND #n0 = :v11 AND " +
+		"contains(#n0,:v12) AND size(#n0) >= :v13"
 	assert.Equal(t, exp, *out)
+	assert.Equal(t, map[string]string{"#n0": "foo"}, a.attributeNames())
+	assert.Len(t, a.attributeValues(), 14)
+	// nil values are stored as NULL, every placeholder must be defined
+	assert.Equal(t, &types.AttributeValueMemberNULL{Value: true}, a.attributeValues()[":v0"])
+	assert.Equal(t, &types.AttributeValueMemberNULL{Value: true}, a.attributeValues()[":v11"])
+}
+
+func TestBuildExpressionGroups(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Operator LogicalOperator
+		Negate   bool
+		In       []Condition
+		Exp      string
+	}{
+		{
+			Name:     "Single group",
+			Operator: And,
+			In: []Condition{
+				Group(Or, Condition{
+					Operator: Equals,
+					Field:    "foo",
+				}, Condition{
+					Operator: GreaterThan,
+					Field:    "bar",
+				}),
+			},
+			Exp: "(#n0 = :v0 OR #n1 > :v1)",
+		},
+		{
+			Name:     "Condition and group",
+			Operator: And,
+			In: []Condition{
+				{
+					Operator: Equals,
+					Field:    "status",
+				},
+				Group(Or, Condition{
+					Operator: GreaterThan,
+					Field:    "amount",
+				}, Condition{
+					Operator: Contains,
+					Field:    "tags",
+				}),
+			},
+			Exp: "#n0 = :v0 AND (#n1 > :v1 OR contains(#n2,:v2))",
+		},
+		{
+			Name: "Missing operator defaults to And",
+			In: []Condition{
+				{
+					Operator: Equals,
+					Field:    "foo",
+				},
+				Group("", Condition{
+					Operator: Equals,
+					Field:    "bar",
+				}, Condition{
+					Operator: Equals,
+					Field:    "baz",
+				}),
+			},
+			Exp: "#n0 = :v0 AND (#n1 = :v1 AND #n2 = :v2)",
+		},
+		{
+			Name:     "Negated nested groups",
+			Operator: Or,
+			Negate:   true,
+			In: []Condition{
+				{
+					Negate:   true,
+					Operator: AttributeExists,
+					Field:    "foo",
+				},
+				func() Condition {
+					c := Group(And, Condition{
+						Operator: Equals,
+						Field:    "bar",
+					}, Group(Or, Condition{
+						Operator: LessThan,
+						Field:    "baz",
+					}, Condition{
+						Operator:          Size,
+						SecondaryOperator: GreaterThan,
+						Field:             "foobar",
+					}))
+					c.Negate = true
+					return c
+				}(),
+			},
+			Exp: "NOT (NOT (attribute_exists(#n0)) OR NOT (#n1 = :v0 AND (#n2 < :v1 OR size(#n3) > :v2)))",
+		},
+		{
+			Name:     "Empty group",
+			Operator: And,
+			In: []Condition{
+				Group(Or),
+				{
+					Operator: Equals,
+					Field:    "foo",
+				},
+			},
+			Exp: "#n0 = :v0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			a := newPlaceholderAllocator()
+			out, err := buildExpression(a, tt.Operator, tt.Negate, tt.In)
+			require.NoError(t, err)
+			require.NotNil(t, out)
+			assert.Equal(t, tt.Exp, *out)
+			nodes, err := compileConditions(newPlaceholderAllocator(), tt.In)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.Exp), calculateExpressionBufferCap(tt.Operator, tt.Negate, nodes))
+		})
+	}
+}
+
+func TestNewExpressionKeyGroup(t *testing.T) {
+	exp := mustNewExpression(t, Or, false, []Condition{
+		func() Condition {
+			c := Group(Or, Condition{
+				Operator: Equals,
+				Field:    "foo",
+				Value:    "bar",
+			}, Condition{
+				Operator: BeginsWith,
+				Field:    "baz",
+				Value:    "ba",
+			})
+			c.IsKey = true
+			return c
+		}(),
+		Group(Or, Condition{
+			Operator: Equals,
+			Field:    "foobar",
+			Value:    1,
+		}, Condition{
+			Operator: AttributeNotExists,
+			Field:    "bar",
+		}),
+	}, nil)
+	require.NotNil(t, exp.KeyExpression)
+	assert.Equal(t, "#n0 = :v0 AND begins_with(#n1,:v1)", *exp.KeyExpression)
+	require.NotNil(t, exp.FilterExpression)
+	assert.Equal(t, "(#n2 = :v2 OR attribute_not_exists(#n3))", *exp.FilterExpression)
+	assert.Len(t, exp.Names, 4)
+	assert.Len(t, exp.Values, 3)
+}
+
+func TestNewExpressionCollisions(t *testing.T) {
+	exp := mustNewExpression(t, And, false, []Condition{
+		{
+			IsKey:    true,
+			Operator: Equals,
+			Field:    "partition-key",
+			Value:    "foo",
+		},
+		{
+			IsKey:    true,
+			Operator: BeginsWith,
+			Field:    "sort_key",
+			Value:    "bar",
+		},
+		{
+			Operator: GreaterOrEqualThan,
+			Field:    "sort_key",
+			Value:    "bar#2",
+		},
+		{
+			Operator: LessOrEqualThan,
+			Field:    "sort_key",
+			Value:    "bar#5",
+		},
+		{
+			Operator: Equals,
+			Field:    "display name",
+			Value:    "baz",
+		},
+	}, nil)
+	require.NotNil(t, exp.KeyExpression)
+	assert.Equal(t, "#n0 = :v0 AND begins_with(#n1,:v1)", *exp.KeyExpression)
+	require.NotNil(t, exp.FilterExpression)
+	assert.Equal(t, "#n1 >= :v2 AND #n1 <= :v3 AND #n2 = :v4", *exp.FilterExpression)
+	assert.Equal(t, map[string]string{
+		"#n0": "partition-key",
+		"#n1": "sort_key",
+		"#n2": "display name",
+	}, exp.Names)
+	assert.Equal(t, map[string]types.AttributeValue{
+		":v0": &types.AttributeValueMemberS{Value: "foo"},
+		":v1": &types.AttributeValueMemberS{Value: "bar"},
+		":v2": &types.AttributeValueMemberS{Value: "bar#2"},
+		":v3": &types.AttributeValueMemberS{Value: "bar#5"},
+		":v4": &types.AttributeValueMemberS{Value: "baz"},
+	}, exp.Values)
+}
+
+func TestNewExpressionEmpty(t *testing.T) {
+	exp := mustNewExpression(t, And, false, []Condition{
+		{
+			Operator: AttributeExists,
+			Field:    "foo",
+		},
+	}, nil)
+	assert.Nil(t, exp.KeyExpression)
+	require.NotNil(t, exp.FilterExpression)
+	assert.Equal(t, "attribute_exists(#n0)", *exp.FilterExpression)
+	assert.Nil(t, exp.Values)
+
+	exp = mustNewExpression(t, And, false, nil, nil)
+	assert.Nil(t, exp.KeyExpression)
+	assert.Nil(t, exp.FilterExpression)
+	assert.Nil(t, exp.Names)
+	assert.Nil(t, exp.Values)
+}
+
+func TestNewExpressionUnsupportedValue(t *testing.T) {
+	_, err := newExpression(And, false, []Condition{
+		{
+			Operator: Equals,
+			Field:    "foo",
+			Value:    "bar",
+		},
+		{
+			Operator: Equals,
+			Field:    "baz",
+			Value:    []string{},
+		},
+	}, nil)
+	assert.ErrorIs(t, err, ErrEmptySet)
+	var attrErr AttributeError
+	require.ErrorAs(t, err, &attrErr)
+	assert.Equal(t, "baz", attrErr.Attribute)
+
+	a := newPlaceholderAllocator()
+	_, err = buildExpression(a, And, false, []Condition{{
+		Operator: Equals,
+		Field:    "foo",
+		Value:    struct{}{},
+	}})
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	assert.Nil(t, a.attributeValues())
+}
+
+func TestNewExpressionDocumentPaths(t *testing.T) {
+	exp := mustNewExpression(t, And, false, []Condition{
+		{
+			IsKey:    true,
+			Operator: Equals,
+			Field:    "partition_key",
+			Value:    "foo",
+		},
+		{
+			Operator: Equals,
+			Field:    Path("address.city"),
+			Value:    "London",
+		},
+		{
+			Operator: BeginsWith,
+			Field:    Path("items[2].sku"),
+			Value:    "SKU",
+		},
+		{
+			Operator: AttributeExists,
+			Field:    "legacy.name", // top-level attribute with a literal dot
+		},
+	}, []string{"partition_key", Path("address.city"), "items", "legacy.name"})
+	require.NotNil(t, exp.KeyExpression)
+	assert.Equal(t, "#n0 = :v0", *exp.KeyExpression)
+	require.NotNil(t, exp.FilterExpression)
+	assert.Equal(t, "#n1.#n2 = :v1 AND begins_with(#n3[2].#n4,:v2) AND attribute_exists(#n5)",
+		*exp.FilterExpression)
+	require.NotNil(t, exp.ProjectionExpression)
+	assert.Equal(t, "#n0,#n1.#n2,#n3,#n5", *exp.ProjectionExpression)
+	assert.Equal(t, map[string]string{
+		"#n0": "partition_key",
+		"#n1": "address",
+		"#n2": "city",
+		"#n3": "items",
+		"#n4": "sku",
+		"#n5": "legacy.name",
+	}, exp.Names)
+}
+
+func TestNewExpressionFieldRef(t *testing.T) {
+	exp := mustNewExpression(t, And, false, []Condition{
+		{
+			Operator: LessThan,
+			Field:    "balance",
+			Value:    FieldRef("credit_limit"),
+		},
+		{
+			Operator: GreaterThan,
+			Field:    "updated_at",
+			Value:    FieldRef(Path("audit.created_at")),
+		},
+		{
+			Operator:    Between,
+			Field:       "amount",
+			Value:       0,
+			ExtraValues: []interface{}{FieldRef("balance")},
+		},
+		{
+			Operator:    In,
+			Field:       "status",
+			Value:       FieldRef("previous_status"),
+			ExtraValues: []interface{}{"ACTIVE"},
+		},
+	}, nil)
+	require.NotNil(t, exp.FilterExpression)
+	assert.Equal(t, "#n0 < #n1 AND #n2 > #n3.#n4 AND #n5 BETWEEN :v0 AND #n0 AND #n6 IN (#n7,:v1)",
+		*exp.FilterExpression)
+	assert.Equal(t, map[string]string{
+		"#n0": "balance",
+		"#n1": "credit_limit",
+		"#n2": "updated_at",
+		"#n3": "audit",
+		"#n4": "created_at",
+		"#n5": "amount",
+		"#n6": "status",
+		"#n7": "previous_status",
+	}, exp.Names)
+	assert.Equal(t, map[string]types.AttributeValue{
+		":v0": &types.AttributeValueMemberN{Value: "0"},
+		":v1": &types.AttributeValueMemberS{Value: "ACTIVE"},
+	}, exp.Values)
+}
+
+func TestNewExpressionSize(t *testing.T) {
+	exp := mustNewExpression(t, Or, false, []Condition{
+		{
+			Operator:          Size,
+			SecondaryOperator: Between,
+			Field:             "items",
+			Value:             1,
+			ExtraValues:       []interface{}{10},
+		},
+		{
+			Negate:            true,
+			Operator:          Size,
+			SecondaryOperator: In,
+			Field:             "tags",
+			Value:             0,
+			ExtraValues:       []interface{}{2, 4},
+		},
+		{
+			Negate:            true,
+			Operator:          Size,
+			SecondaryOperator: GreaterThan,
+			Field:             "items",
+			Value:             FieldRef("max_items"),
+		},
+	}, nil)
+	expStr := "size(#n0) BETWEEN :v0 AND :v1 OR NOT (size(#n1) IN (:v2,:v3,:v4)) OR NOT (size(#n0) > #n2)"
+	require.NotNil(t, exp.FilterExpression)
+	assert.Equal(t, expStr, *exp.FilterExpression)
+	assert.Len(t, exp.Values, 5)
 }
 
 func BenchmarkBuildExpression(b *testing.B) {
@@ -481,6 +875,13 @@ func BenchmarkBuildExpression(b *testing.B) {
 	}
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
-		buildExpression(And, true, conditions)
+		_, _ = buildExpression(newPlaceholderAllocator(), And, true, conditions)
 	}
 }
+
+func mustNewExpression(t *testing.T, op LogicalOperator, negate bool, c []Condition, projected []string) expression {
+	t.Helper()
+	exp, err := newExpression(op, negate, c, projected)
+	require.NoError(t, err)
+	return exp
+}
@@ -1,6 +1,6 @@
 module github.com/maestre3d/dynamoql-go
 
-go 1.17
+go 1.18
 
 require (
 	github.com/aws/aws-sdk-go-v2 v1.16.5
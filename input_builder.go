@@ -7,12 +7,20 @@ import (
 )
 
 // NewQueryInput builds a dynamodb.QueryInput using current QueryBuilder instance values.
-func NewQueryInput(q *QueryBuilder) dynamodb.QueryInput {
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by Query API.
+func NewQueryInput(q *QueryBuilder) (dynamodb.QueryInput, error) {
+	if err := q.validateQuery(); err != nil {
+		return dynamodb.QueryInput{}, err
+	}
 	selectOpt := types.SelectAllAttributes
-	if q.projectedFieldsExpression != nil {
+	if len(q.projectedFields) > 0 {
 		selectOpt = types.SelectSpecificAttributes
 	}
-	builder := newExpression(q.operator, q.negate, q.conditions)
+	builder, err := newExpression(q.operator, q.negate, q.conditions, q.projectedFields)
+	if err != nil {
+		return dynamodb.QueryInput{}, err
+	}
 	return dynamodb.QueryInput{
 		TableName:                 &q.table,
 		ExclusiveStartKey:         q.pageToken,
@@ -23,20 +31,59 @@ func NewQueryInput(q *QueryBuilder) dynamodb.QueryInput {
 		IndexName:                 q.index,
 		KeyConditionExpression:    builder.KeyExpression,
 		Limit:                     &q.limit,
-		ProjectionExpression:      q.projectedFieldsExpression,
+		ProjectionExpression:      builder.ProjectionExpression,
 		ReturnConsumedCapacity:    q.returnMetrics,
 		ScanIndexForward:          aws.Bool(q.ordering == Ascend),
 		Select:                    selectOpt,
+	}, nil
+}
+
+// newScanConditions converts key conditions into filter conditions as Scan API does not accept key expressions.
+//
+// Key conditions are concatenated with an And operator to the original filter conditions, which are grouped to keep
+// their own logical operator and negation.
+func newScanConditions(op LogicalOperator, negate bool, c []Condition) (LogicalOperator, bool, []Condition) {
+	keys := make([]Condition, 0, len(c))
+	attrs := make([]Condition, 0, len(c))
+	for i := range c {
+		if !c[i].IsKey {
+			attrs = append(attrs, c[i])
+			continue
+		}
+		key := c[i]
+		key.IsKey = false
+		key.Negate = false
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return op, negate, c
+	} else if len(attrs) == 0 {
+		return And, false, keys
 	}
+	filters := Group(op, attrs...)
+	filters.Negate = negate
+	return And, false, append(keys, filters)
 }
 
 // NewScanInput builds a dynamodb.ScanInput using current QueryBuilder instance values.
-func NewScanInput(q *QueryBuilder) dynamodb.ScanInput {
+//
+// As Scan API does not accept key expressions, key conditions are used as filters. Segment and TotalSegments are not
+// set as Scan API requires both, use GetParallelScanReader to scan segments in parallel (DegreeOfParallelism).
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by Scan API.
+func NewScanInput(q *QueryBuilder) (dynamodb.ScanInput, error) {
+	if err := q.validateScan(); err != nil {
+		return dynamodb.ScanInput{}, err
+	}
 	selectOpt := types.SelectAllAttributes
-	if q.projectedFieldsExpression != nil {
+	if len(q.projectedFields) > 0 {
 		selectOpt = types.SelectSpecificAttributes
 	}
-	builder := newExpression(q.operator, q.negate, q.conditions)
+	op, negate, conditions := newScanConditions(q.operator, q.negate, q.conditions)
+	builder, err := newExpression(op, negate, conditions, q.projectedFields)
+	if err != nil {
+		return dynamodb.ScanInput{}, err
+	}
 	return dynamodb.ScanInput{
 		TableName:                 &q.table,
 		ConsistentRead:            &q.isConsistent,
@@ -46,22 +93,30 @@ func NewScanInput(q *QueryBuilder) dynamodb.ScanInput {
 		FilterExpression:          builder.FilterExpression,
 		IndexName:                 q.index,
 		Limit:                     &q.limit,
-		ProjectionExpression:      q.projectedFieldsExpression,
+		ProjectionExpression:      builder.ProjectionExpression,
 		ReturnConsumedCapacity:    q.returnMetrics,
-		Segment:                   nil,
 		Select:                    selectOpt,
-		TotalSegments:             &q.parallelDegree,
-	}
+	}, nil
 }
 
 // NewGetInput builds a dynamodb.GetItemInput using current QueryBuilder instance values.
-func NewGetInput(q *QueryBuilder) dynamodb.GetItemInput {
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by GetItem API.
+func NewGetInput(q *QueryBuilder) (dynamodb.GetItemInput, error) {
+	if err := q.validateGet(); err != nil {
+		return dynamodb.GetItemInput{}, err
+	}
+	// GetItem API uses raw keys, hence only the projection expression requires attribute names
+	builder, err := newExpression(q.operator, q.negate, nil, q.projectedFields)
+	if err != nil {
+		return dynamodb.GetItemInput{}, err
+	}
 	return dynamodb.GetItemInput{
 		Key:                      buildExpressionValuesRaw(q.conditions),
 		TableName:                &q.table,
 		ConsistentRead:           &q.isConsistent,
-		ExpressionAttributeNames: nil,
-		ProjectionExpression:     q.projectedFieldsExpression,
+		ExpressionAttributeNames: builder.Names,
+		ProjectionExpression:     builder.ProjectionExpression,
 		ReturnConsumedCapacity:   q.returnMetrics,
-	}
+	}, nil
 }
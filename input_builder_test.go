@@ -5,15 +5,139 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func TestNewGetInput(t *testing.T) {
+	builder := dynamoql.Select("name", "status", dynamoql.Path("address.city")).
+		From("sample").
+		Where(dynamoql.Condition{
+			IsKey:    true,
+			Operator: dynamoql.Equals,
+			Field:    "id",
+			Value:    "123",
+		})
+	out, err := dynamoql.NewGetInput(builder)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: "123"},
+	}, out.Key)
+	require.NotNil(t, out.ProjectionExpression)
+	assert.Equal(t, "#n0,#n1,#n2.#n3", *out.ProjectionExpression)
+	assert.Equal(t, map[string]string{
+		"#n0": "name",
+		"#n1": "status",
+		"#n2": "address",
+		"#n3": "city",
+	}, out.ExpressionAttributeNames)
+
+	out, err = dynamoql.NewGetInput(dynamoql.Select().From("sample").Where(dynamoql.Condition{
+		IsKey:    true,
+		Operator: dynamoql.Equals,
+		Field:    "id",
+		Value:    "123",
+	}))
+	require.NoError(t, err)
+	assert.Nil(t, out.ProjectionExpression)
+	assert.Nil(t, out.ExpressionAttributeNames)
+
+	_, err = dynamoql.NewGetInput(dynamoql.Select().From("sample"))
+	assert.ErrorIs(t, err, dynamoql.ErrMissingKeyCondition)
+}
+
+func TestNewScanInput(t *testing.T) {
+	builder := dynamoql.Select().
+		From("sample").
+		Or().
+		Negate().
+		Where(dynamoql.Condition{
+			IsKey:    true,
+			Operator: dynamoql.Equals,
+			Field:    "partition_key",
+			Value:    "foo",
+		}, dynamoql.Condition{
+			Operator: dynamoql.Equals,
+			Field:    "status",
+			Value:    "ACTIVE",
+		}, dynamoql.Condition{
+			Operator: dynamoql.AttributeExists,
+			Field:    "deleted_at",
+		})
+	out, err := dynamoql.NewScanInput(builder)
+	require.NoError(t, err)
+	assert.Nil(t, out.TotalSegments)
+	assert.Nil(t, out.Segment)
+	assert.Equal(t, types.SelectAllAttributes, out.Select)
+	require.NotNil(t, out.FilterExpression)
+	assert.Equal(t, "#n0 = :v0 AND NOT (#n1 = :v1 OR attribute_exists(#n2))", *out.FilterExpression)
+	assert.Len(t, out.ExpressionAttributeValues, 2)
+
+	out, err = dynamoql.NewScanInput(dynamoql.Select().From("sample").Where(dynamoql.Condition{
+		IsKey:    true,
+		Operator: dynamoql.BeginsWith,
+		Field:    "partition_key",
+		Value:    "foo",
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, out.FilterExpression)
+	assert.Equal(t, "begins_with(#n0,:v0)", *out.FilterExpression)
+}
+
+func TestNewScanInputNilValues(t *testing.T) {
+	out, err := dynamoql.NewScanInput(dynamoql.Select().From("sample").Where(dynamoql.Condition{
+		Operator:    dynamoql.In,
+		Field:       "status",
+		Value:       "ACTIVE",
+		ExtraValues: []interface{}{nil},
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, out.FilterExpression)
+	assert.Equal(t, "#n0 IN (:v0,:v1)", *out.FilterExpression)
+	assert.Equal(t, map[string]types.AttributeValue{
+		":v0": &types.AttributeValueMemberS{Value: "ACTIVE"},
+		":v1": &types.AttributeValueMemberNULL{Value: true},
+	}, out.ExpressionAttributeValues)
+}
+
+func TestNewQueryInputReservedWords(t *testing.T) {
+	builder := dynamoql.Select("date", "size", "status").
+		From("sample").
+		Where(dynamoql.Condition{
+			IsKey:    true,
+			Operator: dynamoql.Equals,
+			Field:    "name",
+			Value:    "foo",
+		}, dynamoql.Condition{
+			Operator: dynamoql.Equals,
+			Field:    "status",
+			Value:    "ACTIVE",
+		})
+	expNames := map[string]string{
+		"#n0": "name",
+		"#n1": "status",
+		"#n2": "date",
+		"#n3": "size",
+	}
+
+	out, err := dynamoql.NewQueryInput(builder)
+	require.NoError(t, err)
+	assert.Equal(t, types.SelectSpecificAttributes, out.Select)
+	assert.Equal(t, "#n0 = :v0", *out.KeyConditionExpression)
+	assert.Equal(t, "#n1 = :v1", *out.FilterExpression)
+	assert.Equal(t, "#n2,#n3,#n1", *out.ProjectionExpression)
+	assert.Equal(t, expNames, out.ExpressionAttributeNames)
+
+	outScan, err := dynamoql.NewScanInput(builder)
+	require.NoError(t, err)
+	assert.Equal(t, "#n2,#n3,#n1", *outScan.ProjectionExpression)
+	assert.Equal(t, expNames, outScan.ExpressionAttributeNames)
+}
+
 func BenchmarkNewGetInput(b *testing.B) {
 	builder := dynamoql.Select("foo").
 		From("sample").
-		Index("sample-gsi").
-		And().
 		Negate().
-		Or(). // replaces And
 		Where(dynamoql.Condition{
 			Negate:            false,
 			IsKey:             true,
@@ -26,10 +150,9 @@ func BenchmarkNewGetInput(b *testing.B) {
 		StrongConsistency().
 		OrderBy(dynamoql.Descend).
 		Limit(10).
-		DegreeOfParallelism(25).
 		Metrics(types.ReturnConsumedCapacityIndexes)
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
-		_ = dynamoql.NewGetInput(builder)
+		_, _ = dynamoql.NewGetInput(builder)
 	}
 }
@@ -0,0 +1,355 @@
+package dynamoql
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrMissingAttribute a required attribute is not present in an item.
+var ErrMissingAttribute = errors.New("dynamoql: Missing attribute")
+
+// FieldError a failure decoding a single attribute of an item.
+type FieldError struct {
+	// Attribute name or document path of the attribute.
+	Attribute string
+	// Expected Amazon DynamoDB attribute type (e.g. S, N, M).
+	Expected string
+	// Actual Amazon DynamoDB attribute type, empty if the attribute is missing.
+	Actual string
+	// Err underlying error, either ErrCannotCastAttribute, ErrMissingAttribute or a parsing error (e.g. a number
+	// overflow).
+	Err error
+}
+
+var _ error = FieldError{}
+
+func (e FieldError) Error() string {
+	prefix := "dynamoql: Attribute " + strconv.Quote(e.Attribute) + ": "
+	switch {
+	case errors.Is(e.Err, ErrMissingAttribute):
+		return prefix + "missing"
+	case errors.Is(e.Err, ErrCannotCastAttribute):
+		return prefix + "expected " + e.Expected + ", got " + e.Actual
+	default:
+		return prefix + e.Err.Error()
+	}
+}
+
+// Unwrap retrieves the underlying error.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError every failure found by an ItemDecoder. Use errors.Is to match any of the underlying errors.
+type DecodeError struct {
+	Errors []FieldError
+}
+
+var _ error = DecodeError{}
+
+func (e DecodeError) Error() string {
+	buf := strings.Builder{}
+	buf.WriteString("dynamoql: Cannot decode item (" + strconv.Itoa(len(e.Errors)) + " errors)")
+	for i := range e.Errors {
+		buf.WriteString("; ")
+		buf.WriteString(strings.TrimPrefix(e.Errors[i].Error(), "dynamoql: "))
+	}
+	return buf.String()
+}
+
+// Is indicates if any of the underlying errors matches target.
+func (e DecodeError) Is(target error) bool {
+	for i := range e.Errors {
+		if errors.Is(e.Errors[i], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemDecoder reads typed attributes of an Amazon DynamoDB item, collecting every failure instead of stopping (or
+// silently returning zero-values like MustParse* functions do).
+//
+// Attributes are read by either name or document path (e.g. address.city or items[0].sku). Missing and NULL
+// attributes are decoded into zero-values, use Require to report missing attributes.
+//
+// e.g.
+//
+//	func (s *Student) UnmarshalDynamoDB(m map[string]types.AttributeValue) error {
+//		d := dynamoql.NewItemDecoder(m).Require("partition_key")
+//		s.StudentID = dynamoql.ParseCompositeKey(d.String("partition_key"))
+//		s.City = d.String("address.city")
+//		s.Tags = d.StringSet("tags")
+//		return d.Err()
+//	}
+type ItemDecoder struct {
+	item   map[string]types.AttributeValue
+	errors []FieldError
+}
+
+// NewItemDecoder allocates an ItemDecoder for the given item.
+func NewItemDecoder(item map[string]types.AttributeValue) *ItemDecoder {
+	return &ItemDecoder{item: item}
+}
+
+// Err retrieves a DecodeError with every failure found, nil if none.
+func (d *ItemDecoder) Err() error {
+	if len(d.errors) == 0 {
+		return nil
+	}
+	return DecodeError{Errors: d.errors}
+}
+
+// Require reports an ErrMissingAttribute failure for each of the given attributes not present in the item.
+func (d *ItemDecoder) Require(paths ...string) *ItemDecoder {
+	for _, p := range paths {
+		if !d.Has(p) {
+			d.errors = append(d.errors, FieldError{
+				Attribute: p,
+				Err:       ErrMissingAttribute,
+			})
+		}
+	}
+	return d
+}
+
+// Has indicates if the given attribute is present in the item (NULL attributes included).
+func (d *ItemDecoder) Has(path string) bool {
+	attr, _, _ := d.lookup(path)
+	return attr != nil
+}
+
+// Attribute retrieves the given attribute as is, nil if missing.
+func (d *ItemDecoder) Attribute(path string) types.AttributeValue {
+	return d.get(path)
+}
+
+// String reads the given S attribute.
+func (d *ItemDecoder) String(path string) string {
+	return decodeAttribute(d, path, "S", ParseString)
+}
+
+// Bool reads the given BOOL attribute.
+func (d *ItemDecoder) Bool(path string) bool {
+	return decodeAttribute(d, path, "BOOL", ParseBool)
+}
+
+// Int reads the given N attribute as an integer.
+func (d *ItemDecoder) Int(path string) int {
+	return decodeAttribute(d, path, "N", ParseInt)
+}
+
+// Int64 reads the given N attribute as a 64-bit integer.
+func (d *ItemDecoder) Int64(path string) int64 {
+	return decodeAttribute(d, path, "N", ParseInt64)
+}
+
+// Uint reads the given N attribute as an unsigned integer.
+func (d *ItemDecoder) Uint(path string) uint {
+	return decodeAttribute(d, path, "N", ParseUint)
+}
+
+// Uint64 reads the given N attribute as a 64-bit unsigned integer.
+func (d *ItemDecoder) Uint64(path string) uint64 {
+	return decodeAttribute(d, path, "N", ParseUint64)
+}
+
+// Float64 reads the given N attribute as a 64-bit floating point.
+func (d *ItemDecoder) Float64(path string) float64 {
+	return decodeAttribute(d, path, "N", ParseFloat64)
+}
+
+// Decimal reads the given N attribute as an arbitrary-precision decimal number.
+func (d *ItemDecoder) Decimal(path string) Decimal {
+	return decodeAttribute(d, path, "N", ParseDecimal)
+}
+
+// BigInt reads the given N attribute as an arbitrary-precision integer.
+func (d *ItemDecoder) BigInt(path string) *big.Int {
+	return decodeAttribute(d, path, "N", ParseBigInt)
+}
+
+// BigFloat reads the given N attribute as an arbitrary-precision floating point.
+func (d *ItemDecoder) BigFloat(path string) *big.Float {
+	return decodeAttribute(d, path, "N", ParseBigFloat)
+}
+
+// BigRat reads the given N attribute as an arbitrary-precision rational number.
+func (d *ItemDecoder) BigRat(path string) *big.Rat {
+	return decodeAttribute(d, path, "N", ParseBigRat)
+}
+
+// Time reads the given attribute as time.Time (see ParseTime).
+func (d *ItemDecoder) Time(path string) time.Time {
+	return decodeAttribute(d, path, "S or N", ParseTime)
+}
+
+// Duration reads the given attribute as time.Duration (see ParseDuration).
+func (d *ItemDecoder) Duration(path string) time.Duration {
+	return decodeAttribute(d, path, "N or S", ParseDuration)
+}
+
+// Binary reads the given B attribute.
+func (d *ItemDecoder) Binary(path string) []byte {
+	return decodeAttribute(d, path, "B", ParseBinary)
+}
+
+// StringSet reads the given SS attribute.
+func (d *ItemDecoder) StringSet(path string) []string {
+	return decodeAttribute(d, path, "SS", ParseStringSet)
+}
+
+// IntSet reads the given NS attribute as a set of integers.
+func (d *ItemDecoder) IntSet(path string) []int {
+	return decodeAttribute(d, path, "NS", ParseIntSet)
+}
+
+// Float64Set reads the given NS attribute as a set of 64-bit floating points.
+func (d *ItemDecoder) Float64Set(path string) []float64 {
+	return decodeAttribute(d, path, "NS", ParseFloat64Set)
+}
+
+// BinarySet reads the given BS attribute.
+func (d *ItemDecoder) BinarySet(path string) [][]byte {
+	return decodeAttribute(d, path, "BS", ParseBinarySet)
+}
+
+// Map reads the given M attribute.
+func (d *ItemDecoder) Map(path string) map[string]types.AttributeValue {
+	return decodeAttribute(d, path, "M", ParseMap)
+}
+
+// List reads the given L attribute.
+func (d *ItemDecoder) List(path string) []types.AttributeValue {
+	return decodeAttribute(d, path, "L", ParseList)
+}
+
+// Interface reads the given attribute into its natural Go type (e.g. S into string, N into Decimal, L into
+// []interface{} and M into map[string]interface{}).
+func (d *ItemDecoder) Interface(path string) interface{} {
+	return decodeAttribute(d, path, "", decodeInterface)
+}
+
+// Decode reads the given M attribute into v using its UnmarshalDynamoDB method.
+func (d *ItemDecoder) Decode(path string, v Unmarshaler) {
+	m := d.Map(path)
+	if m == nil {
+		return
+	}
+	if err := v.UnmarshalDynamoDB(m); err != nil {
+		d.errors = append(d.errors, FieldError{
+			Attribute: path,
+			Expected:  "M",
+			Actual:    "M",
+			Err:       err,
+		})
+	}
+}
+
+// decodeAttribute reads the attribute at path using parse function, reporting a FieldError with the expected
+// attribute type if parse fails. Missing and NULL attributes are decoded into zero-values.
+func decodeAttribute[T any](d *ItemDecoder, path, expected string, parse func(types.AttributeValue) (T, error)) T {
+	var zero T
+	attr := d.get(path)
+	if attr == nil || IsNull(attr) {
+		return zero
+	}
+	v, err := parse(attr)
+	if err != nil {
+		d.errors = append(d.errors, FieldError{
+			Attribute: path,
+			Expected:  expected,
+			Actual:    attributeType(attr),
+			Err:       err,
+		})
+		return zero
+	}
+	return v
+}
+
+// get retrieves the attribute at path, reporting a FieldError if an intermediate element of the path has an
+// unexpected type.
+func (d *ItemDecoder) get(path string) types.AttributeValue {
+	attr, expected, actual := d.lookup(path)
+	if expected != "" {
+		d.errors = append(d.errors, FieldError{
+			Attribute: path,
+			Expected:  expected,
+			Actual:    actual,
+			Err:       ErrCannotCastAttribute,
+		})
+	}
+	return attr
+}
+
+// lookup retrieves the attribute at path, attribute names take precedence over document paths (e.g. an attribute
+// named "address.city"). If an intermediate element is neither a map nor a list, lookup returns the expected and
+// actual attribute types.
+func (d *ItemDecoder) lookup(path string) (attr types.AttributeValue, expected, actual string) {
+	path = fieldName(path)
+	if attr, ok := d.item[path]; ok {
+		return attr, "", ""
+	}
+	elems, ok := parseDocumentPath(path)
+	if !ok {
+		return nil, "", ""
+	}
+	attr = d.item[elems[0].name]
+	for _, elem := range elems[1:] {
+		switch v := attr.(type) {
+		case nil:
+			return nil, "", ""
+		case *types.AttributeValueMemberM:
+			if elem.isIndex {
+				return nil, "L", "M"
+			}
+			attr = v.Value[elem.name]
+		case *types.AttributeValueMemberL:
+			if !elem.isIndex {
+				return nil, "M", "L"
+			} else if elem.index >= len(v.Value) {
+				return nil, "", ""
+			}
+			attr = v.Value[elem.index]
+		default:
+			if elem.isIndex {
+				return nil, "L", attributeType(attr)
+			}
+			return nil, "M", attributeType(attr)
+		}
+	}
+	return attr, "", ""
+}
+
+// attributeType retrieves the Amazon DynamoDB type name of the given attribute (e.g. S, N or M).
+func attributeType(v types.AttributeValue) string {
+	switch v.(type) {
+	case *types.AttributeValueMemberS:
+		return "S"
+	case *types.AttributeValueMemberN:
+		return "N"
+	case *types.AttributeValueMemberB:
+		return "B"
+	case *types.AttributeValueMemberBOOL:
+		return "BOOL"
+	case *types.AttributeValueMemberNULL:
+		return "NULL"
+	case *types.AttributeValueMemberSS:
+		return "SS"
+	case *types.AttributeValueMemberNS:
+		return "NS"
+	case *types.AttributeValueMemberBS:
+		return "BS"
+	case *types.AttributeValueMemberM:
+		return "M"
+	case *types.AttributeValueMemberL:
+		return "L"
+	default:
+		return ""
+	}
+}
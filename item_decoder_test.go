@@ -0,0 +1,75 @@
+package dynamoql_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemDecoder(t *testing.T) {
+	createdAt := time.Date(2022, 5, 1, 10, 30, 0, 0, time.UTC)
+	bill, _ := Bill{InvoiceID: "1", BillID: "2", Amount: "10"}.MarshalDynamoDB()
+	item := map[string]types.AttributeValue{
+		"PK":           dynamoql.FormatAttribute("STUDENT#1"),
+		"age":          dynamoql.FormatAttribute(21),
+		"active":       dynamoql.FormatAttribute(true),
+		"created_at":   dynamoql.FormatAttribute(createdAt),
+		"tags":         dynamoql.FormatAttribute([]string{"foo", "bar"}),
+		"nickname":     &types.AttributeValueMemberNULL{Value: true},
+		"address.city": dynamoql.FormatAttribute("Raw"),
+		"address": dynamoql.FormatAttribute(map[string]interface{}{
+			"city":  "CDMX",
+			"zip":   11000,
+			"lines": []interface{}{"Street 1", "Apt 2"},
+		}),
+		"bill": &types.AttributeValueMemberM{Value: bill},
+	}
+
+	d := dynamoql.NewItemDecoder(item).Require("PK", "age")
+	assert.Equal(t, "STUDENT#1", d.String("PK"))
+	assert.Equal(t, 21, d.Int("age"))
+	assert.True(t, d.Bool("active"))
+	assert.Equal(t, createdAt, d.Time("created_at"))
+	assert.Equal(t, []string{"foo", "bar"}, d.StringSet("tags"))
+	assert.Equal(t, "", d.String("nickname"))
+	assert.Equal(t, "", d.String("missing"))
+	assert.Equal(t, "Raw", d.String("address.city"))
+	assert.Equal(t, 11000, d.Int("address.zip"))
+	assert.Equal(t, "Apt 2", d.String("address.lines[1]"))
+	assert.Equal(t, "", d.String("address.lines[5]"))
+	assert.Equal(t, map[string]interface{}{"city": "CDMX", "zip": dynamoql.Decimal("11000"),
+		"lines": []interface{}{"Street 1", "Apt 2"}}, d.Interface("address"))
+	var b Bill
+	d.Decode("bill", &b)
+	assert.Equal(t, Bill{InvoiceID: "1", BillID: "2", Amount: "10"}, b)
+	assert.True(t, d.Has("nickname"))
+	assert.False(t, d.Has("address.country"))
+	assert.NoError(t, d.Err())
+
+	d = dynamoql.NewItemDecoder(item).Require("SK")
+	assert.Equal(t, 0, d.Int("PK"))
+	assert.Equal(t, "", d.String("age"))
+	assert.Equal(t, "", d.String("address.lines.first"))
+	assert.Equal(t, "", d.String("tags[0]"))
+	err := d.Err()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, dynamoql.ErrMissingAttribute)
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+	var errDecode dynamoql.DecodeError
+	require.True(t, errors.As(err, &errDecode))
+	assert.Equal(t, []dynamoql.FieldError{
+		{Attribute: "SK", Err: dynamoql.ErrMissingAttribute},
+		{Attribute: "PK", Expected: "N", Actual: "S", Err: dynamoql.ErrCannotCastAttribute},
+		{Attribute: "age", Expected: "S", Actual: "N", Err: dynamoql.ErrCannotCastAttribute},
+		{Attribute: "address.lines.first", Expected: "M", Actual: "L", Err: dynamoql.ErrCannotCastAttribute},
+		{Attribute: "tags[0]", Expected: "L", Actual: "SS", Err: dynamoql.ErrCannotCastAttribute},
+	}, errDecode.Errors)
+	assert.EqualError(t, err, `dynamoql: Cannot decode item (5 errors); Attribute "SK": missing; `+
+		`Attribute "PK": expected N, got S; Attribute "age": expected S, got N; `+
+		`Attribute "address.lines.first": expected M, got L; Attribute "tags[0]": expected L, got SS`)
+}
@@ -1,9 +1,15 @@
 package dynamoql
 
 import (
+	"errors"
+	"reflect"
+
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+// ErrInvalidUnmarshalTarget the given value cannot be used to decode Amazon DynamoDB items.
+var ErrInvalidUnmarshalTarget = errors.New("dynamoql: Invalid unmarshal target")
+
 // Marshaler converts an Amazon DynamoDB model into a primitive map.
 type Marshaler interface {
 	MarshalDynamoDB() (map[string]types.AttributeValue, error)
@@ -13,3 +19,93 @@ type Marshaler interface {
 type Unmarshaler interface {
 	UnmarshalDynamoDB(map[string]types.AttributeValue) error
 }
+
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+
+// Marshal converts the given struct (or pointer to struct) into an Amazon DynamoDB item using its struct tags
+// (StructTagName).
+//
+// Nested fields implementing Marshaler are converted using their MarshalDynamoDB method. The given value itself is
+// always converted using struct tags, so it is safe to call Marshal from a MarshalDynamoDB implementation.
+//
+// e.g.
+//
+//	type Student struct {
+//		_           struct{}  `dynamoql:"Student,schema"`
+//		StudentID   string    `dynamoql:"partition_key,composite=STUDENT"`
+//		DisplayName string    `dynamoql:"display_name,omitempty"`
+//		Tags        []string  `dynamoql:"tags,set"`
+//		EnrolledAt  time.Time `dynamoql:"enrolled_at,unixtime"`
+//	}
+//
+//	func (s Student) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
+//		return dynamoql.Marshal(s)
+//	}
+func Marshal(v interface{}) (map[string]types.AttributeValue, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedType
+	}
+	return encodeStruct(rv)
+}
+
+// Unmarshal decodes the given Amazon DynamoDB item into v using its struct tags (StructTagName). Attributes missing
+// from item are left untouched.
+//
+// v MUST be a non-nil pointer to a struct. Nested fields implementing Unmarshaler are decoded using their
+// UnmarshalDynamoDB method. The given value itself is always decoded using struct tags, so it is safe to call
+// Unmarshal from an UnmarshalDynamoDB implementation.
+//
+// Returns ErrInvalidSchema if v has a schema field and item holds another schema name. Items without schema name
+// (DefaultSchemaField) are accepted.
+func Unmarshal(item map[string]types.AttributeValue, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidUnmarshalTarget
+	}
+	return decodeStruct(item, rv.Elem())
+}
+
+// UnmarshalItems decodes the given items, appending them into v.
+//
+// v MUST be a pointer to a slice of either values or pointers of structs (e.g. *[]Bill or *[]*Bill). Items are decoded
+// using UnmarshalDynamoDB if the struct pointer implements Unmarshaler, Unmarshal otherwise.
+func UnmarshalItems(items []map[string]types.AttributeValue, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return ErrInvalidUnmarshalTarget
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Pointer
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	isUnmarshaler := reflect.PointerTo(elemType).Implements(unmarshalerType)
+	if !isUnmarshaler && elemType.Kind() != reflect.Struct {
+		return ErrInvalidUnmarshalTarget
+	}
+	buf := reflect.MakeSlice(slice.Type(), 0, slice.Len()+len(items))
+	buf = reflect.AppendSlice(buf, slice)
+	for i := range items {
+		elem := reflect.New(elemType)
+		var err error
+		if isUnmarshaler {
+			err = elem.Interface().(Unmarshaler).UnmarshalDynamoDB(items[i])
+		} else {
+			err = decodeStruct(items[i], elem.Elem())
+		}
+		if err != nil {
+			return err
+		}
+		if !isPtr {
+			elem = elem.Elem()
+		}
+		buf = reflect.Append(buf, elem)
+	}
+	slice.Set(buf)
+	return nil
+}
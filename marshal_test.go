@@ -0,0 +1,197 @@
+package dynamoql_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalItems(t *testing.T) {
+	items := []map[string]types.AttributeValue{
+		Bill{InvoiceID: "1", BillID: "2", Amount: "10"}.GetKeys(),
+		Bill{InvoiceID: "1", BillID: "3", Amount: "10"}.GetKeys(),
+	}
+
+	var bills []Bill
+	require.NoError(t, dynamoql.UnmarshalItems(items, &bills))
+	assert.Equal(t, []Bill{
+		{InvoiceID: "1", BillID: "2"},
+		{InvoiceID: "1", BillID: "3"},
+	}, bills)
+	require.NoError(t, dynamoql.UnmarshalItems(items[:1], &bills))
+	assert.Len(t, bills, 3)
+
+	var billPtrs []*Bill
+	require.NoError(t, dynamoql.UnmarshalItems(items, &billPtrs))
+	assert.Equal(t, []*Bill{
+		{InvoiceID: "1", BillID: "2"},
+		{InvoiceID: "1", BillID: "3"},
+	}, billPtrs)
+
+	var strs []string
+	assert.ErrorIs(t, dynamoql.UnmarshalItems(items, &strs), dynamoql.ErrInvalidUnmarshalTarget)
+	assert.ErrorIs(t, dynamoql.UnmarshalItems(items, bills), dynamoql.ErrInvalidUnmarshalTarget)
+	assert.ErrorIs(t, dynamoql.UnmarshalItems(items, nil), dynamoql.ErrInvalidUnmarshalTarget)
+	assert.ErrorIs(t, dynamoql.UnmarshalItems(items, &Bill{}), dynamoql.ErrInvalidUnmarshalTarget)
+}
+
+type Address struct {
+	City    string `dynamoql:"city"`
+	ZipCode *int   `dynamoql:"zip_code,omitempty"`
+}
+
+type Audit struct {
+	CreatedBy string
+	UpdatedAt time.Time `dynamoql:"updated_at,unixtime"`
+	SeenAt    time.Time `dynamoql:"seen_at,unixmilli"`
+}
+
+type Student struct {
+	_           struct{}          `dynamoql:"Student,schema"`
+	StudentID   string            `dynamoql:"PK,composite=STUDENT"`
+	SortKey     string            `dynamoql:"SK"`
+	DisplayName string            `dynamoql:"display_name,omitempty"`
+	Grades      []int             `dynamoql:"grades,set"`
+	Tags        []string          `dynamoql:"tags,set"`
+	Scores      []float64         `dynamoql:"scores"`
+	Enrolled    bool              `dynamoql:"enrolled"`
+	Address     *Address          `dynamoql:"address"`
+	Metadata    map[string]string `dynamoql:"metadata,omitempty"`
+	Extra       interface{}       `dynamoql:"extra,omitempty"`
+	Bill        Bill              `dynamoql:"bill"`
+	Ignored     string            `dynamoql:"-"`
+	internal    string
+	Audit
+}
+
+func TestMarshal(t *testing.T) {
+	zip := 11000
+	updatedAt := time.Date(2022, 5, 1, 10, 30, 0, 0, time.UTC)
+	s := Student{
+		StudentID: "1",
+		SortKey:   "root",
+		Grades:    []int{9, 10},
+		Scores:    []float64{9.5},
+		Enrolled:  true,
+		Address:   &Address{City: "CDMX", ZipCode: &zip},
+		Extra:     map[string]interface{}{"nickname": "joe", "age": dynamoql.Decimal("21")},
+		Bill:      Bill{InvoiceID: "1", BillID: "2"},
+		Ignored:   "foo",
+		internal:  "bar",
+		Audit:     Audit{CreatedBy: "admin", UpdatedAt: updatedAt, SeenAt: updatedAt.Add(time.Millisecond)},
+	}
+	item, err := dynamoql.Marshal(&s)
+	require.NoError(t, err)
+	exp := map[string]types.AttributeValue{
+		dynamoql.DefaultSchemaField: &types.AttributeValueMemberS{Value: "Student"},
+		"PK":                        &types.AttributeValueMemberS{Value: "STUDENT#1"},
+		"SK":                        &types.AttributeValueMemberS{Value: "root"},
+		"grades":                    &types.AttributeValueMemberNS{Value: []string{"9", "10"}},
+		"tags":                      &types.AttributeValueMemberNULL{Value: true},
+		"scores": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberN{Value: "9.5"},
+		}},
+		"enrolled": &types.AttributeValueMemberBOOL{Value: true},
+		"address": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"city":     &types.AttributeValueMemberS{Value: "CDMX"},
+			"zip_code": &types.AttributeValueMemberN{Value: "11000"},
+		}},
+		"extra": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"nickname": &types.AttributeValueMemberS{Value: "joe"},
+			"age":      &types.AttributeValueMemberN{Value: "21"},
+		}},
+		"bill": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"PK":          &types.AttributeValueMemberS{Value: "I#1"},
+			"SK":          &types.AttributeValueMemberS{Value: "B#2"},
+			"BillAmount":  &types.AttributeValueMemberS{Value: ""},
+			"BillBalance": &types.AttributeValueMemberS{Value: ""},
+		}},
+		"CreatedBy":  &types.AttributeValueMemberS{Value: "admin"},
+		"updated_at": &types.AttributeValueMemberN{Value: "1651401000"},
+		"seen_at":    &types.AttributeValueMemberN{Value: "1651401000001"},
+	}
+	assert.Equal(t, exp, item)
+
+	var out Student
+	require.NoError(t, dynamoql.Unmarshal(item, &out))
+	s.Ignored, s.internal = "", ""
+	assert.Equal(t, s, out)
+
+	_, err = dynamoql.Marshal("foo")
+	assert.ErrorIs(t, err, dynamoql.ErrUnsupportedType)
+	_, err = dynamoql.Marshal(struct {
+		Ch chan int
+	}{})
+	var errAttr dynamoql.AttributeError
+	require.True(t, errors.As(err, &errAttr))
+	assert.Equal(t, "Ch", errAttr.Attribute)
+	assert.ErrorIs(t, err, dynamoql.ErrUnsupportedType)
+	_, err = dynamoql.Marshal(Student{Tags: []string{}})
+	assert.ErrorIs(t, err, dynamoql.ErrEmptySet)
+	item, err = dynamoql.Marshal(struct {
+		Tags []string `dynamoql:"tags,set,omitempty"`
+	}{Tags: []string{}})
+	require.NoError(t, err)
+	assert.Empty(t, item)
+	_, err = dynamoql.Marshal(struct {
+		Count int `dynamoql:"count,composite=C"`
+	}{})
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidStructTag)
+	_, err = dynamoql.Marshal(struct {
+		_ struct{} `dynamoql:",schema"`
+	}{})
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidStructTag)
+}
+
+func TestUnmarshal(t *testing.T) {
+	var s Student
+	assert.ErrorIs(t, dynamoql.Unmarshal(nil, s), dynamoql.ErrInvalidUnmarshalTarget)
+	assert.ErrorIs(t, dynamoql.Unmarshal(map[string]types.AttributeValue{
+		dynamoql.DefaultSchemaField: dynamoql.FormatAttribute("Bill"),
+	}, &s), dynamoql.ErrInvalidSchema)
+	// items without schema attribute (e.g. projected items) are accepted
+	require.NoError(t, dynamoql.Unmarshal(map[string]types.AttributeValue{
+		"display_name": dynamoql.FormatAttribute("Joe"),
+	}, &s))
+	assert.Equal(t, "Joe", s.DisplayName)
+	s = Student{}
+
+	err := dynamoql.Unmarshal(map[string]types.AttributeValue{
+		dynamoql.DefaultSchemaField: dynamoql.FormatAttribute("Student"),
+		"grades":                    dynamoql.FormatAttribute("A"),
+	}, &s)
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+	assert.EqualError(t, err, `dynamoql: Attribute "grades": dynamoql: Cannot cast attribute`)
+
+	// numbers keep their precision
+	var extra struct {
+		Amount  interface{} `dynamoql:"amount"`
+		Amounts interface{} `dynamoql:"amounts"`
+	}
+	require.NoError(t, dynamoql.Unmarshal(map[string]types.AttributeValue{
+		"amount":  &types.AttributeValueMemberN{Value: "12345678901234567890.123"},
+		"amounts": &types.AttributeValueMemberNS{Value: []string{"1.50", "2"}},
+	}, &extra))
+	assert.Equal(t, dynamoql.Decimal("12345678901234567890.123"), extra.Amount)
+	assert.Equal(t, []dynamoql.Decimal{"1.50", "2"}, extra.Amounts)
+	err = dynamoql.Unmarshal(map[string]types.AttributeValue{
+		"amounts": &types.AttributeValueMemberNS{Value: []string{"1", "abc"}},
+	}, &extra)
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidNumber)
+
+	items := []map[string]types.AttributeValue{
+		{
+			"city":     dynamoql.FormatAttribute("CDMX"),
+			"zip_code": &types.AttributeValueMemberNULL{Value: true},
+		},
+		{"city": dynamoql.FormatAttribute("GDL")},
+	}
+	var addresses []Address
+	require.NoError(t, dynamoql.UnmarshalItems(items, &addresses))
+	assert.Equal(t, []Address{{City: "CDMX"}, {City: "GDL"}}, addresses)
+}
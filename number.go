@@ -0,0 +1,306 @@
+package dynamoql
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Amazon DynamoDB number limits.
+//
+// Numbers can have up to 38 digits of precision and must be either zero or in the range of 1E-130 to
+// 9.9999999999999999999999999999999999999E+125 (positive and negative). Leading and trailing zeroes are trimmed.
+const (
+	// MaxNumberPrecision maximum number of significant digits of a number.
+	MaxNumberPrecision = 38
+	// MaxNumberExponent maximum decimal exponent of a number, in scientific notation (e.g. 9.9E+125).
+	MaxNumberExponent = 125
+	// MinNumberExponent minimum decimal exponent of a non-zero number, in scientific notation (e.g. 1E-130).
+	MinNumberExponent = -130
+)
+
+// bigFloatPrecision mantissa precision (in bits) of big.Float values parsed by ParseBigFloat, enough to hold
+// MaxNumberPrecision decimal digits.
+const bigFloatPrecision = 128
+
+var (
+	// ErrInvalidNumber the given value is not a valid decimal number (e.g. 12.5, -3 or 1.5E+10).
+	ErrInvalidNumber = errors.New("dynamoql: Invalid number")
+	// ErrNumberPrecision the given number has more significant digits than MaxNumberPrecision or cannot be
+	// represented as a finite decimal (e.g. 1/3).
+	ErrNumberPrecision = errors.New("dynamoql: Number exceeds precision limit")
+	// ErrNumberOutOfRange the given number is out of Amazon DynamoDB number range.
+	ErrNumberOutOfRange = errors.New("dynamoql: Number out of range")
+)
+
+var (
+	decimalType  = reflect.TypeOf(Decimal(""))
+	bigIntType   = reflect.TypeOf((*big.Int)(nil))
+	bigFloatType = reflect.TypeOf((*big.Float)(nil))
+	bigRatType   = reflect.TypeOf((*big.Rat)(nil))
+)
+
+// Decimal an arbitrary-precision decimal number (e.g. 7087796.00), stored as its string representation to avoid
+// rounding errors of floating points.
+//
+// Decimal values are converted into N attributes. Use NewDecimal to validate a number beforehand, otherwise
+// MarshalAttribute reports invalid numbers. The zero value (empty string) holds no number and is converted into NULL,
+// just like nil *big.Int, *big.Float and *big.Rat values.
+type Decimal string
+
+// NewDecimal allocates a Decimal from s, validating it against Amazon DynamoDB number limits.
+func NewDecimal(s string) (Decimal, error) {
+	if err := ValidateNumber(s); err != nil {
+		return "", err
+	}
+	return Decimal(s), nil
+}
+
+// String retrieves the string representation of the number.
+func (d Decimal) String() string {
+	return string(d)
+}
+
+// Rat converts the number into a big.Rat. Returns nil if the number is not valid.
+func (d Decimal) Rat() *big.Rat {
+	if ValidateNumber(string(d)) != nil {
+		return nil
+	}
+	r, _ := new(big.Rat).SetString(string(d))
+	return r
+}
+
+// ValidateNumber checks s is a decimal number (e.g. 12.5, -3 or 1.5E+10) within Amazon DynamoDB number limits.
+//
+// Returns ErrInvalidNumber, ErrNumberPrecision or ErrNumberOutOfRange.
+func ValidateNumber(s string) error {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	// positions of digits are counted from the first digit of the mantissa, ignoring the decimal point
+	digits, point, first, last := 0, -1, -1, -1
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c == '.' && point < 0 {
+			point = digits
+			continue
+		} else if c < '0' || c > '9' {
+			break
+		}
+		if c != '0' {
+			if first < 0 {
+				first = digits
+			}
+			last = digits
+		}
+		digits++
+	}
+	if digits == 0 {
+		return ErrInvalidNumber
+	} else if point < 0 {
+		point = digits
+	}
+
+	exp := 0
+	if i < len(s) {
+		if s[i] != 'e' && s[i] != 'E' {
+			return ErrInvalidNumber
+		}
+		var err error
+		exp, err = strconv.Atoi(s[i+1:])
+		if errors.Is(err, strconv.ErrRange) && first >= 0 {
+			return ErrNumberOutOfRange
+		} else if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return ErrInvalidNumber
+		}
+	}
+	if first < 0 {
+		return nil // zero
+	} else if last-first+1 > MaxNumberPrecision {
+		return ErrNumberPrecision
+	}
+	if adjusted := point - first - 1 + exp; adjusted > MaxNumberExponent || adjusted < MinNumberExponent {
+		return ErrNumberOutOfRange
+	}
+	return nil
+}
+
+// formatNumberValue converts arbitrary-precision numbers (Decimal, *big.Int, *big.Float or *big.Rat) into an N
+// attribute, validated against Amazon DynamoDB number limits. Empty Decimal values are converted into NULL.
+func formatNumberValue(v interface{}) (types.AttributeValue, error) {
+	var s string
+	switch val := v.(type) {
+	case Decimal:
+		if val == "" {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		}
+		s = string(val)
+	case *big.Int:
+		s = val.String()
+	case *big.Float:
+		if val.IsInf() {
+			return nil, ErrNumberOutOfRange
+		}
+		s = val.Text('f', -1)
+	case *big.Rat:
+		var err error
+		if s, err = formatBigRat(val); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnsupportedType
+	}
+	if err := ValidateNumber(s); err != nil {
+		return nil, err
+	}
+	return &types.AttributeValueMemberN{Value: s}, nil
+}
+
+// formatBigRat converts r into its exact decimal representation. A fraction has a finite decimal representation only
+// if its denominator has no prime factors other than 2 and 5.
+func formatBigRat(r *big.Rat) (string, error) {
+	if r.IsInt() {
+		return r.Num().String(), nil
+	}
+	denom := new(big.Int).Set(r.Denom())
+	twos := int(denom.TrailingZeroBits())
+	denom.Rsh(denom, uint(twos))
+	fives := 0
+	five, quo, rem := big.NewInt(5), new(big.Int), new(big.Int)
+	for {
+		quo.QuoRem(denom, five, rem)
+		if rem.Sign() != 0 {
+			break
+		}
+		denom.Set(quo)
+		fives++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return "", ErrNumberPrecision
+	}
+	if fives > twos {
+		return r.FloatString(fives), nil
+	}
+	return r.FloatString(twos), nil
+}
+
+// isNumberType indicates if t is an arbitrary-precision number type (Decimal, *big.Int, *big.Float or *big.Rat).
+func isNumberType(t reflect.Type) bool {
+	return t == decimalType || t == bigIntType || t == bigFloatType || t == bigRatType
+}
+
+// parseNumberValue converts the given Amazon DynamoDB attribute into an arbitrary-precision number of type t.
+func parseNumberValue(v types.AttributeValue, t reflect.Type) (interface{}, error) {
+	switch t {
+	case decimalType:
+		return ParseDecimal(v)
+	case bigIntType:
+		return ParseBigInt(v)
+	case bigFloatType:
+		return ParseBigFloat(v)
+	default:
+		return ParseBigRat(v)
+	}
+}
+
+// parseNumber retrieves the value of the given N attribute, validated against Amazon DynamoDB number limits. Returns an
+// empty string if v is NULL.
+func parseNumber(v types.AttributeValue) (string, error) {
+	if IsNull(v) {
+		return "", nil
+	}
+	data, ok := v.(*types.AttributeValueMemberN)
+	if !ok {
+		return "", ErrCannotCastAttribute
+	}
+	if err := ValidateNumber(data.Value); err != nil {
+		return "", err
+	}
+	return data.Value, nil
+}
+
+// ParseDecimal converts the given Amazon DynamoDB attribute into Decimal. NULL attributes are converted into an empty
+// Decimal.
+func ParseDecimal(v types.AttributeValue) (Decimal, error) {
+	s, err := parseNumber(v)
+	return Decimal(s), err
+}
+
+// ParseBigInt converts the given Amazon DynamoDB attribute into an arbitrary-precision integer. Numbers with a
+// fractional part return ErrInvalidNumber. NULL attributes are converted into nil.
+func ParseBigInt(v types.AttributeValue) (*big.Int, error) {
+	s, err := parseNumber(v)
+	if err != nil || s == "" {
+		return nil, err
+	}
+	if n, ok := new(big.Int).SetString(s, 10); ok {
+		return n, nil
+	}
+	// scientific notation (e.g. 1.5E+3)
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || !r.IsInt() {
+		return nil, ErrInvalidNumber
+	}
+	return r.Num(), nil
+}
+
+// ParseBigFloat converts the given Amazon DynamoDB attribute into an arbitrary-precision floating point, with enough
+// precision to hold MaxNumberPrecision digits. NULL attributes are converted into nil.
+func ParseBigFloat(v types.AttributeValue) (*big.Float, error) {
+	s, err := parseNumber(v)
+	if err != nil || s == "" {
+		return nil, err
+	}
+	f, _, err := big.ParseFloat(s, 10, bigFloatPrecision, big.ToNearestEven)
+	return f, err
+}
+
+// ParseBigRat converts the given Amazon DynamoDB attribute into an arbitrary-precision rational number. NULL attributes
+// are converted into nil.
+func ParseBigRat(v types.AttributeValue) (*big.Rat, error) {
+	s, err := parseNumber(v)
+	if err != nil || s == "" {
+		return nil, err
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, ErrInvalidNumber
+	}
+	return r, nil
+}
+
+// MustParseDecimal converts the given Amazon DynamoDB attribute into Decimal.
+//
+// If fails to parse attribute, returns nil or zero-value.
+func MustParseDecimal(v types.AttributeValue) Decimal {
+	d, _ := ParseDecimal(v)
+	return d
+}
+
+// MustParseBigInt converts the given Amazon DynamoDB attribute into an arbitrary-precision integer.
+//
+// If fails to parse attribute, returns nil or zero-value.
+func MustParseBigInt(v types.AttributeValue) *big.Int {
+	n, _ := ParseBigInt(v)
+	return n
+}
+
+// MustParseBigFloat converts the given Amazon DynamoDB attribute into an arbitrary-precision floating point.
+//
+// If fails to parse attribute, returns nil or zero-value.
+func MustParseBigFloat(v types.AttributeValue) *big.Float {
+	f, _ := ParseBigFloat(v)
+	return f
+}
+
+// MustParseBigRat converts the given Amazon DynamoDB attribute into an arbitrary-precision rational number.
+//
+// If fails to parse attribute, returns nil or zero-value.
+func MustParseBigRat(v types.AttributeValue) *big.Rat {
+	r, _ := ParseBigRat(v)
+	return r
+}
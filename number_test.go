@@ -0,0 +1,243 @@
+package dynamoql_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		err  error
+	}{
+		{name: "Zero", in: "0"},
+		{name: "Negative zero", in: "-0.000"},
+		{name: "Integer", in: "7087796"},
+		{name: "Decimal", in: "7087796.00"},
+		{name: "Signed", in: "+12.5"},
+		{name: "Exponent", in: "1.5E+10"},
+		{name: "Max precision", in: "12345678901234567890123456789012345678"},
+		{name: "Trailing zeroes", in: "1234567890123456789012345678901234567800000"},
+		{name: "Leading zeroes", in: "0.0000012345678901234567890123456789012345678"},
+		{name: "Max", in: "9.9999999999999999999999999999999999999E+125"},
+		{name: "Min", in: "1E-130"},
+		{name: "Negative max", in: "-9.9999999999999999999999999999999999999E+125"},
+		{name: "Negative min", in: "-1E-130"},
+		{name: "Zero huge exponent", in: "0E+99999999999999999999"},
+		{name: "Empty", in: "", err: dynamoql.ErrInvalidNumber},
+		{name: "Sign", in: "-", err: dynamoql.ErrInvalidNumber},
+		{name: "Point", in: ".", err: dynamoql.ErrInvalidNumber},
+		{name: "Currency", in: "$7,087,796.00", err: dynamoql.ErrInvalidNumber},
+		{name: "Spaces", in: "7087796.00 ", err: dynamoql.ErrInvalidNumber},
+		{name: "Empty exponent", in: "1E", err: dynamoql.ErrInvalidNumber},
+		{name: "Fraction", in: "1/3", err: dynamoql.ErrInvalidNumber},
+		{name: "NaN", in: "NaN", err: dynamoql.ErrInvalidNumber},
+		{name: "Precision", in: "123456789012345678901234567890123456789", err: dynamoql.ErrNumberPrecision},
+		{name: "Too large", in: "1E+126", err: dynamoql.ErrNumberOutOfRange},
+		{name: "Too small", in: "0.9E-130", err: dynamoql.ErrNumberOutOfRange},
+		{name: "Negative too large", in: "-10E+125", err: dynamoql.ErrNumberOutOfRange},
+		{name: "Huge exponent", in: "1E+99999999999999999999", err: dynamoql.ErrNumberOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, dynamoql.ValidateNumber(tt.in), tt.err)
+		})
+	}
+}
+
+func TestNewDecimal(t *testing.T) {
+	d, err := dynamoql.NewDecimal("7087796.00")
+	require.NoError(t, err)
+	assert.Equal(t, "7087796.00", d.String())
+	assert.Equal(t, big.NewRat(708779600, 100), d.Rat())
+
+	_, err = dynamoql.NewDecimal("$7,087,796.00")
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidNumber)
+	assert.Nil(t, dynamoql.Decimal("foo").Rat())
+}
+
+func TestMarshalAttribute_Numbers(t *testing.T) {
+	precise, _ := new(big.Int).SetString("12345678901234567890123456789012345678", 10)
+	imprecise, _ := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+	tests := []struct {
+		name string
+		in   interface{}
+		exp  types.AttributeValue
+		err  error
+	}{
+		{
+			name: "Decimal",
+			in:   dynamoql.Decimal("7087796.00"),
+			exp:  &types.AttributeValueMemberN{Value: "7087796.00"},
+		},
+		{
+			name: "Empty decimal",
+			in:   dynamoql.Decimal(""),
+			exp:  &types.AttributeValueMemberNULL{Value: true},
+		},
+		{
+			name: "Invalid decimal",
+			in:   dynamoql.Decimal("$7,087,796.00"),
+			err:  dynamoql.ErrInvalidNumber,
+		},
+		{
+			name: "Big int",
+			in:   precise,
+			exp:  &types.AttributeValueMemberN{Value: "12345678901234567890123456789012345678"},
+		},
+		{
+			name: "Big int precision",
+			in:   imprecise,
+			err:  dynamoql.ErrNumberPrecision,
+		},
+		{
+			name: "Nil big int",
+			in:   (*big.Int)(nil),
+			exp:  &types.AttributeValueMemberNULL{Value: true},
+		},
+		{
+			name: "Big float",
+			in:   big.NewFloat(-1234.5),
+			exp:  &types.AttributeValueMemberN{Value: "-1234.5"},
+		},
+		{
+			name: "Big float infinite",
+			in:   new(big.Float).SetInf(false),
+			err:  dynamoql.ErrNumberOutOfRange,
+		},
+		{
+			name: "Big float out of range",
+			in:   new(big.Float).SetMantExp(big.NewFloat(1), 512),
+			err:  dynamoql.ErrNumberOutOfRange,
+		},
+		{
+			name: "Big rat",
+			in:   big.NewRat(708779601, 100),
+			exp:  &types.AttributeValueMemberN{Value: "7087796.01"},
+		},
+		{
+			name: "Big rat integer",
+			in:   big.NewRat(10, 2),
+			exp:  &types.AttributeValueMemberN{Value: "5"},
+		},
+		{
+			name: "Big rat powers of two and five",
+			in:   big.NewRat(1, 40),
+			exp:  &types.AttributeValueMemberN{Value: "0.025"},
+		},
+		{
+			name: "Big rat inexact",
+			in:   big.NewRat(1, 3),
+			err:  dynamoql.ErrNumberPrecision,
+		},
+		{
+			name: "Nested",
+			in:   map[string]interface{}{"amount": dynamoql.Decimal("1E+126")},
+			err:  dynamoql.ErrNumberOutOfRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := dynamoql.MarshalAttribute(tt.in)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.exp, out)
+		})
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	out, err := dynamoql.ParseDecimal(&types.AttributeValueMemberN{Value: "7087796.00"})
+	assert.NoError(t, err)
+	assert.Equal(t, dynamoql.Decimal("7087796.00"), out)
+	_, err = dynamoql.ParseDecimal(&types.AttributeValueMemberS{Value: "7087796.00"})
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+	_, err = dynamoql.ParseDecimal(&types.AttributeValueMemberN{Value: "1E+126"})
+	assert.ErrorIs(t, err, dynamoql.ErrNumberOutOfRange)
+	assert.Equal(t, dynamoql.Decimal(""), dynamoql.MustParseDecimal(&types.AttributeValueMemberS{}))
+	out, err = dynamoql.ParseDecimal(&types.AttributeValueMemberNULL{Value: true})
+	assert.NoError(t, err)
+	assert.Equal(t, dynamoql.Decimal(""), out)
+}
+
+func TestParseBigInt(t *testing.T) {
+	exp, _ := new(big.Int).SetString("-12345678901234567890123456789012345678", 10)
+	out, err := dynamoql.ParseBigInt(&types.AttributeValueMemberN{Value: exp.String()})
+	assert.NoError(t, err)
+	assert.Equal(t, exp, out)
+	out, err = dynamoql.ParseBigInt(&types.AttributeValueMemberN{Value: "1.5E+3"})
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1500), out)
+	_, err = dynamoql.ParseBigInt(&types.AttributeValueMemberN{Value: "1.5"})
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidNumber)
+	assert.Nil(t, dynamoql.MustParseBigInt(&types.AttributeValueMemberS{}))
+	out, err = dynamoql.ParseBigInt(&types.AttributeValueMemberNULL{Value: true})
+	assert.NoError(t, err)
+	assert.Nil(t, out)
+}
+
+func TestParseBigFloat(t *testing.T) {
+	out, err := dynamoql.ParseBigFloat(&types.AttributeValueMemberN{Value: "7087796.01"})
+	assert.NoError(t, err)
+	assert.Equal(t, "7087796.01", out.Text('f', 2))
+	out, err = dynamoql.ParseBigFloat(&types.AttributeValueMemberN{Value: "1.2345678901234567890123456789012345678"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2345678901234567890123456789012345678", out.Text('f', 37))
+	assert.Nil(t, dynamoql.MustParseBigFloat(&types.AttributeValueMemberS{}))
+}
+
+func TestParseBigRat(t *testing.T) {
+	out, err := dynamoql.ParseBigRat(&types.AttributeValueMemberN{Value: "7087796.01"})
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(708779601, 100), out)
+	_, err = dynamoql.ParseBigRat(&types.AttributeValueMemberN{Value: "1/3"})
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidNumber)
+	assert.Nil(t, dynamoql.MustParseBigRat(&types.AttributeValueMemberS{}))
+}
+
+func TestMarshal_Numbers(t *testing.T) {
+	type invoice struct {
+		Balance dynamoql.Decimal   `dynamoql:"balance"`
+		Amounts []dynamoql.Decimal `dynamoql:"amounts,set"`
+		Credits *big.Int           `dynamoql:"credits"`
+		Rate    *big.Rat           `dynamoql:"rate,omitempty"`
+	}
+	in := invoice{
+		Balance: "7087796.00",
+		Amounts: []dynamoql.Decimal{"1.50", "2"},
+		Credits: big.NewInt(42),
+	}
+	item, err := dynamoql.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.AttributeValue{
+		"balance": &types.AttributeValueMemberN{Value: "7087796.00"},
+		"amounts": &types.AttributeValueMemberNS{Value: []string{"1.50", "2"}},
+		"credits": &types.AttributeValueMemberN{Value: "42"},
+	}, item)
+
+	var out invoice
+	require.NoError(t, dynamoql.Unmarshal(item, &out))
+	assert.Equal(t, in, out)
+
+	// zero values are stored as NULL
+	item, err = dynamoql.Marshal(invoice{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.AttributeValue{
+		"balance": &types.AttributeValueMemberNULL{Value: true},
+		"amounts": &types.AttributeValueMemberNULL{Value: true},
+		"credits": &types.AttributeValueMemberNULL{Value: true},
+	}, item)
+	out = invoice{}
+	require.NoError(t, dynamoql.Unmarshal(item, &out))
+	assert.Equal(t, invoice{}, out)
+
+	_, err = dynamoql.Marshal(invoice{Balance: "1E+126"})
+	assert.ErrorIs(t, err, dynamoql.ErrNumberOutOfRange)
+	_, err = dynamoql.Marshal(invoice{Balance: "0", Amounts: []dynamoql.Decimal{"foo"}})
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidNumber)
+}
@@ -3,11 +3,22 @@ package dynamoql
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"sort"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+// ErrInvalidPageToken the given page token is malformed.
+var ErrInvalidPageToken = errors.New("dynamoql: Invalid page token")
+
 const (
+	// pageTokenVersion current encoding format version, written as the first byte of a token. Legacy tokens start
+	// with an attribute type instead.
+	pageTokenVersion = 0x01
+
+	// legacy format separators
 	pageTokenSeparator    = '~'
 	pageTokenKeySeparator = '&'
 
@@ -21,7 +32,10 @@ const (
 //
 // Note: Last Evaluate Key(s) is the primary key of a DynamoDB table. Primary keys accept String, Binary and Number
 // DynamoDB types and have a maximum length of 2 keys (Partition Key and Sort Key, which compose a composite key
-// if both present).
+// if both present). Queries and scans over secondary indexes also include the index keys.
+//
+// Tokens are encoded deterministically (i.e. the same keys always produce the same token) and any String, Number or
+// Binary value is preserved. Tokens encoded by previous versions are still decoded.
 //
 // See ref: https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/HowItWorks.CoreComponents.html
 type PageToken map[string]types.AttributeValue
@@ -47,58 +61,56 @@ func NewPageToken(rawStr string) (PageToken, error) {
 }
 
 func (t PageToken) toBinary() []byte {
-	// Page Token format:
-	//
-	// Partition Key only:
-	//
-	// Attr_Type~Attr_name~Attr_val
+	// Page Token format (version 1):
 	//
-	// Partition Key and Sort Key (Composite Key):
+	// Version Key_count Key_0 Key_1 ... Key_n
 	//
-	// Attr_Type~Attr_name~Attr_val&Attr_Type~Attr_name~Attr_val
+	// Where each key is:
 	//
-	// Or
+	// Attr_Type Attr_name_length Attr_name Attr_val_length Attr_val
 	//
-	// Key_0&Key_1
-	buffer := bytes.NewBuffer(nil)
-	// Note: Might require to use a LinkedHashMap to preserve insertion-order.
-	count := 0
+	// Version and Attr_Type are single bytes while lengths and Key_count are unsigned varints. Keys are sorted by
+	// name, so the same key always produces the same token.
+	names := make([]string, 0, len(t))
 	for k := range t {
-		if count > 1 {
-			// ignore extra keys, a primary is composed by a Partition Key and a Sort key only
-			break
-		}
 		switch t[k].(type) {
+		case *types.AttributeValueMemberS, *types.AttributeValueMemberN, *types.AttributeValueMemberB:
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteByte(pageTokenVersion)
+	writePageTokenUvarint(buffer, uint64(len(names)))
+	for _, k := range names {
+		var val []byte
+		switch attr := t[k].(type) {
 		case *types.AttributeValueMemberS:
 			buffer.WriteByte(pageTokenAttrTypeString)
-			buffer.WriteByte(pageTokenSeparator)
-			_, _ = buffer.WriteString(k)
-			buffer.WriteByte(pageTokenSeparator)
-			attr := t[k].(*types.AttributeValueMemberS)
-			_, _ = buffer.WriteString(attr.Value)
+			val = []byte(attr.Value)
 		case *types.AttributeValueMemberN:
 			buffer.WriteByte(pageTokenAttrTypeNumber)
-			buffer.WriteByte(pageTokenSeparator)
-			_, _ = buffer.WriteString(k)
-			buffer.WriteByte(pageTokenSeparator)
-			attr := t[k].(*types.AttributeValueMemberN)
-			_, _ = buffer.WriteString(attr.Value)
+			val = []byte(attr.Value)
 		case *types.AttributeValueMemberB:
 			buffer.WriteByte(pageTokenAttrTypeBinary)
-			buffer.WriteByte(pageTokenSeparator)
-			_, _ = buffer.WriteString(k)
-			buffer.WriteByte(pageTokenSeparator)
-			attr := t[k].(*types.AttributeValueMemberB)
-			buffer.Write(attr.Value)
+			val = attr.Value
 		}
-		if count < len(t)-1 {
-			buffer.WriteByte(pageTokenKeySeparator)
-		}
-		count++
+		writePageTokenUvarint(buffer, uint64(len(k)))
+		_, _ = buffer.WriteString(k)
+		writePageTokenUvarint(buffer, uint64(len(val)))
+		_, _ = buffer.Write(val)
 	}
 	return buffer.Bytes()
 }
 
+// writePageTokenUvarint writes v into buffer as an unsigned varint.
+func writePageTokenUvarint(buffer *bytes.Buffer, v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	_, _ = buffer.Write(scratch[:n])
+}
+
 func (t PageToken) append(attrType byte, key string, val []byte) {
 	switch attrType {
 	case pageTokenAttrTypeString:
@@ -117,7 +129,71 @@ func (t PageToken) append(attrType byte, key string, val []byte) {
 }
 
 func (t PageToken) fromBinary(raw []byte) error {
-	// Page Token format:
+	if len(raw) == 0 {
+		return nil
+	} else if raw[0] != pageTokenVersion {
+		t.fromLegacyBinary(raw)
+		return nil
+	}
+
+	raw = raw[1:]
+	count, err := readPageTokenUvarint(&raw)
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < count; i++ {
+		if len(raw) == 0 {
+			return ErrInvalidPageToken
+		}
+		attrType := raw[0]
+		if attrType != pageTokenAttrTypeString && attrType != pageTokenAttrTypeNumber &&
+			attrType != pageTokenAttrTypeBinary {
+			return ErrInvalidPageToken
+		}
+		raw = raw[1:]
+		name, err := readPageTokenBytes(&raw)
+		if err != nil {
+			return err
+		}
+		val, err := readPageTokenBytes(&raw)
+		if err != nil {
+			return err
+		}
+		t.append(attrType, string(name), append([]byte{}, val...))
+	}
+	if len(raw) > 0 {
+		return ErrInvalidPageToken
+	}
+	return nil
+}
+
+// readPageTokenUvarint reads an unsigned varint from raw, advancing it.
+func readPageTokenUvarint(raw *[]byte) (uint64, error) {
+	v, n := binary.Uvarint(*raw)
+	if n <= 0 {
+		return 0, ErrInvalidPageToken
+	}
+	*raw = (*raw)[n:]
+	return v, nil
+}
+
+// readPageTokenBytes reads a length-prefixed byte sequence from raw, advancing it.
+func readPageTokenBytes(raw *[]byte) ([]byte, error) {
+	size, err := readPageTokenUvarint(raw)
+	if err != nil {
+		return nil, err
+	} else if size > uint64(len(*raw)) {
+		return nil, ErrInvalidPageToken
+	}
+	val := (*raw)[:size]
+	*raw = (*raw)[size:]
+	return val, nil
+}
+
+// fromLegacyBinary decodes tokens encoded before format versioning was introduced. Names and values containing
+// separators cannot be decoded properly, hence these tokens are no longer produced.
+func (t PageToken) fromLegacyBinary(raw []byte) {
+	// Legacy Page Token format:
 	//
 	// Partition Key only:
 	//
@@ -131,7 +207,7 @@ func (t PageToken) fromBinary(raw []byte) error {
 	//
 	// Key_0&Key_1
 	if len(raw) < 2 {
-		return nil
+		return
 	}
 
 	var attrType byte
@@ -172,11 +248,13 @@ charLoop:
 			t.append(attrType, name, val)
 		}
 	}
-	return nil
 }
 
 // Encode transforms the current PageToken into a base64 URL-safe string.
 func (t PageToken) Encode() string {
+	if len(t) == 0 {
+		return ""
+	}
 	return base64.URLEncoding.EncodeToString(t.toBinary())
 }
 
@@ -187,7 +265,8 @@ func (t PageToken) String() string {
 	return t.Encode()
 }
 
-// Decode converts given base64 URL-safe string into a PageToken.
+// Decode converts given base64 URL-safe string into a PageToken. Returns ErrInvalidPageToken if the decoded token
+// is malformed.
 func (t PageToken) Decode(encodedRaw string) error {
 	data, err := base64.URLEncoding.DecodeString(encodedRaw)
 	if err != nil {
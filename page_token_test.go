@@ -1,6 +1,7 @@
 package dynamoql_test
 
 import (
+	"encoding/base64"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -27,7 +28,7 @@ func TestPageToken_Encode(t *testing.T) {
 					Value: "123-abc",
 				},
 			},
-			Exp: "U35hdXRob3JfaWR-MTIzLWFiYw==",
+			Exp: "AQFTCWF1dGhvcl9pZAcxMjMtYWJj",
 		},
 		{
 			Name: "Valid Partition Key only Binary",
@@ -36,7 +37,7 @@ func TestPageToken_Encode(t *testing.T) {
 					Value: []byte("123-abc"),
 				},
 			},
-			Exp: "Qn5hdXRob3JfaWR-MTIzLWFiYw==",
+			Exp: "AQFCCWF1dGhvcl9pZAcxMjMtYWJj",
 		},
 		{
 			Name: "Valid Partition Key only Number",
@@ -45,7 +46,7 @@ func TestPageToken_Encode(t *testing.T) {
 					Value: "123",
 				},
 			},
-			Exp: "Tn50aW1lc3RhbXBfdW5peH4xMjM=",
+			Exp: "AQFODnRpbWVzdGFtcF91bml4AzEyMw==",
 		},
 		{
 			Name: "Valid Composite Key",
@@ -57,7 +58,7 @@ func TestPageToken_Encode(t *testing.T) {
 					Value: "123",
 				},
 			},
-			Exp: "U351c2VyX2lkfjEyMy1hYmMmTn50aW1lc3RhbXBfdW5peH4xMjM=",
+			Exp: "AQJODnRpbWVzdGFtcF91bml4AzEyM1MHdXNlcl9pZAcxMjMtYWJj",
 		},
 	}
 
@@ -81,7 +82,7 @@ func TestPageToken_Decode(t *testing.T) {
 			Exp:   "",
 		},
 		{
-			Name: "Valid Partition Key only String",
+			Name: "Legacy Partition Key only String",
 			Token: dynamoql.PageToken{
 				"author_id": &types.AttributeValueMemberS{
 					Value: "123-abc",
@@ -90,7 +91,7 @@ func TestPageToken_Decode(t *testing.T) {
 			Exp: "U35hdXRob3JfaWR-MTIzLWFiYw==",
 		},
 		{
-			Name: "Valid Partition Key only Binary",
+			Name: "Legacy Partition Key only Binary",
 			Token: dynamoql.PageToken{
 				"author_id": &types.AttributeValueMemberB{
 					Value: []byte("123-abc"),
@@ -99,7 +100,7 @@ func TestPageToken_Decode(t *testing.T) {
 			Exp: "Qn5hdXRob3JfaWR-MTIzLWFiYw==",
 		},
 		{
-			Name: "Valid Partition Key only Number",
+			Name: "Legacy Partition Key only Number",
 			Token: dynamoql.PageToken{
 				"timestamp_unix": &types.AttributeValueMemberN{
 					Value: "123",
@@ -108,7 +109,7 @@ func TestPageToken_Decode(t *testing.T) {
 			Exp: "Tn50aW1lc3RhbXBfdW5peH4xMjM=",
 		},
 		{
-			Name: "Valid Composite Key",
+			Name: "Legacy Composite Key",
 			Token: dynamoql.PageToken{
 				"user_id": &types.AttributeValueMemberS{
 					Value: "123-abc",
@@ -119,6 +120,27 @@ func TestPageToken_Decode(t *testing.T) {
 			},
 			Exp: "U351c2VyX2lkfjEyMy1hYmMmTn50aW1lc3RhbXBfdW5peH4xMjM=",
 		},
+		{
+			Name: "Valid Partition Key only String",
+			Token: dynamoql.PageToken{
+				"author_id": &types.AttributeValueMemberS{
+					Value: "123-abc",
+				},
+			},
+			Exp: "AQFTCWF1dGhvcl9pZAcxMjMtYWJj",
+		},
+		{
+			Name: "Valid Composite Key",
+			Token: dynamoql.PageToken{
+				"user_id": &types.AttributeValueMemberS{
+					Value: "123-abc",
+				},
+				"timestamp_unix": &types.AttributeValueMemberN{
+					Value: "123",
+				},
+			},
+			Exp: "AQJODnRpbWVzdGFtcF91bml4AzEyM1MHdXNlcl9pZAcxMjMtYWJj",
+		},
 	}
 
 	for _, tt := range tests {
@@ -131,6 +153,110 @@ func TestPageToken_Decode(t *testing.T) {
 	}
 }
 
+func TestPageToken_Decode_Invalid(t *testing.T) {
+	tests := []struct {
+		Name string
+		Raw  []byte
+	}{
+		{
+			Name: "Missing count",
+			Raw:  []byte{0x01},
+		},
+		{
+			Name: "Missing key",
+			Raw:  []byte{0x01, 0x01},
+		},
+		{
+			Name: "Unknown attribute type",
+			Raw:  []byte{0x01, 0x01, 'X', 0x01, 'k', 0x01, 'v'},
+		},
+		{
+			Name: "Name overflow",
+			Raw:  []byte{0x01, 0x01, 'S', 0x05, 'k'},
+		},
+		{
+			Name: "Value overflow",
+			Raw:  []byte{0x01, 0x01, 'S', 0x01, 'k', 0x05, 'v'},
+		},
+		{
+			Name: "Trailing bytes",
+			Raw:  []byte{0x01, 0x01, 'S', 0x01, 'k', 0x01, 'v', 'x'},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			token := dynamoql.PageToken{}
+			err := token.Decode(base64.URLEncoding.EncodeToString(tt.Raw))
+			assert.ErrorIs(t, err, dynamoql.ErrInvalidPageToken)
+		})
+	}
+}
+
+func TestPageToken_RoundTrip(t *testing.T) {
+	token := dynamoql.PageToken{
+		"partition_key": &types.AttributeValueMemberS{
+			Value: "London:Westminster~10",
+		},
+		"sort_key": &types.AttributeValueMemberS{
+			Value: "A&B~C",
+		},
+		"gsi_key~&": &types.AttributeValueMemberB{
+			Value: []byte{0x00, '~', '&', 0xff},
+		},
+		"timestamp_unix": &types.AttributeValueMemberN{
+			Value: "-1.5E+10",
+		},
+	}
+	encoded := token.Encode()
+	for i := 0; i < 10; i++ {
+		require.Equal(t, encoded, token.Encode())
+	}
+
+	out, err := dynamoql.NewPageToken(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, token, out)
+}
+
+func FuzzPageToken_RoundTrip(f *testing.F) {
+	f.Add("partition_key", "London:Westminster~10", "sort_key", "123", []byte("A&B"))
+	f.Add("", "", "~", "&", []byte{})
+	f.Fuzz(func(t *testing.T, sName, sVal, nName, nVal string, bVal []byte) {
+		token := dynamoql.PageToken{
+			sName: &types.AttributeValueMemberS{Value: sVal},
+			nName: &types.AttributeValueMemberN{Value: nVal},
+		}
+		if bVal != nil {
+			token[sName+nName+"_b"] = &types.AttributeValueMemberB{Value: bVal}
+		}
+		encoded := token.Encode()
+		require.Equal(t, encoded, token.Encode())
+
+		out, err := dynamoql.NewPageToken(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, token, out)
+	})
+}
+
+func FuzzPageToken_Decode(f *testing.F) {
+	f.Add("U351c2VyX2lkfjEyMy1hYmMmTn50aW1lc3RhbXBfdW5peH4xMjM=")
+	f.Add("AQJODnRpbWVzdGFtcF91bml4AzEyM1MHdXNlcl9pZAcxMjMtYWJj")
+	f.Add("AQ==")
+	f.Fuzz(func(t *testing.T, raw string) {
+		token := dynamoql.PageToken{}
+		if err := token.Decode(raw); err != nil {
+			return
+		}
+		// decoded tokens must be re-encoded into an equivalent token
+		out, err := dynamoql.NewPageToken(token.Encode())
+		require.NoError(t, err)
+		assert.Equal(t, len(token), len(out))
+		for k, v := range token {
+			assert.Equal(t, v, out[k])
+		}
+	})
+}
+
 func BenchmarkPageToken_Encode(b *testing.B) {
 	token := dynamoql.PageToken{
 		"user_id": &types.AttributeValueMemberS{
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 type QueryPaginator struct {
@@ -19,8 +20,9 @@ func NewQueryPaginator(pageSize int32, c *dynamodb.Client, q dynamodb.QueryInput
 		q.Limit = &pageSize
 	}
 	return &QueryPaginator{
-		client: c,
-		query:  q,
+		client:      c,
+		query:       q,
+		lastEvalKey: q.ExclusiveStartKey, // resumes from the given page token, if any
 	}
 }
 
@@ -51,3 +53,66 @@ func (p *QueryPaginator) GetPage(ctx context.Context) (*dynamodb.QueryOutput, er
 	p.itemCount += out.Count
 	return out, err
 }
+
+func (p *QueryPaginator) GetItems(ctx context.Context) ([]map[string]types.AttributeValue, error) {
+	out, err := p.GetPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+type ScanPaginator struct {
+	client       *dynamodb.Client
+	query        dynamodb.ScanInput
+	lastEvalKey  PageToken
+	scannedPages uint32
+	itemCount    int32
+}
+
+func NewScanPaginator(pageSize int32, c *dynamodb.Client, q dynamodb.ScanInput) *ScanPaginator {
+	if pageSize > 0 {
+		q.Limit = &pageSize
+	}
+	return &ScanPaginator{
+		client:      c,
+		query:       q,
+		lastEvalKey: q.ExclusiveStartKey, // resumes from the given page token, if any
+	}
+}
+
+func (p ScanPaginator) NextPageToken() PageToken {
+	return p.lastEvalKey
+}
+
+func (p ScanPaginator) Next() bool {
+	return p.lastEvalKey.String() != "" || p.scannedPages == 0
+}
+
+func (p ScanPaginator) ScannedPages() uint32 {
+	return p.scannedPages
+}
+
+func (p ScanPaginator) Count() int32 {
+	return p.itemCount
+}
+
+func (p *ScanPaginator) GetPage(ctx context.Context) (*dynamodb.ScanOutput, error) {
+	p.query.ExclusiveStartKey = p.lastEvalKey
+	out, err := p.client.Scan(ctx, &p.query)
+	if err != nil {
+		return nil, err
+	}
+	p.lastEvalKey = out.LastEvaluatedKey
+	p.scannedPages++
+	p.itemCount += out.Count
+	return out, err
+}
+
+func (p *ScanPaginator) GetItems(ctx context.Context) ([]map[string]types.AttributeValue, error) {
+	out, err := p.GetPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/maestre3d/dynamoql-go"
 	"github.com/stretchr/testify/assert"
@@ -50,13 +51,13 @@ func (s *queryPaginatorTestSuite) TestQueryPaginator_GetPage() {
 		},
 		{
 			name:     "Empty query with table name",
-			query:    dynamoql.NewQueryInput(dynamoql.Select().From("InvoiceAndBills")),
+			query:    dynamodb.QueryInput{TableName: aws.String("InvoiceAndBills")}, // missing key condition
 			pageSize: 0,
 			wantErr:  true,
 		},
 		{
 			name: "Invalid query",
-			query: dynamoql.NewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+			query: mustNewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
 				IsKey:    true,
 				Operator: dynamoql.Equals,
 				Field:    "PK",
@@ -72,7 +73,7 @@ func (s *queryPaginatorTestSuite) TestQueryPaginator_GetPage() {
 		},
 		{
 			name: "Valid",
-			query: dynamoql.NewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+			query: mustNewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
 				IsKey:    true,
 				Operator: dynamoql.Equals,
 				Field:    "PK",
@@ -112,3 +113,48 @@ func (s *queryPaginatorTestSuite) TestQueryPaginator_GetPage() {
 		})
 	}
 }
+
+func (s *queryPaginatorTestSuite) TestScanPaginator_GetPage() {
+	tests := []struct {
+		name     string
+		query    dynamodb.ScanInput
+		pageSize int32
+		expItems int32
+		wantErr  bool
+	}{
+		{
+			name:     "Empty query",
+			query:    dynamodb.ScanInput{}, // missing table
+			pageSize: 0,
+			wantErr:  true,
+		},
+		{
+			name: "Valid",
+			query: mustNewScanInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+				Operator: dynamoql.Equals,
+				Field:    "SK",
+				Value:    "root",
+			})),
+			pageSize: 500,
+			expItems: 780,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			p := dynamoql.NewScanPaginator(tt.pageSize, s.client, tt.query)
+			ctx := context.Background()
+			itemBuf := dynamoql.NewItemBuffer(int(tt.expItems))
+			for p.Next() {
+				out, err := p.GetPage(ctx)
+				require.Equal(t, tt.wantErr, err != nil)
+				if err != nil {
+					break
+				}
+				itemBuf.WriteItems(out.Items)
+			}
+			assert.Nil(t, p.NextPageToken())
+			assert.Equal(t, p.Count(), tt.expItems)
+			assert.Equal(t, itemBuf.Len(), int(tt.expItems))
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package dynamoql
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SegmentErrorPolicy Used to set the behaviour of a ParallelScanReader when a segment fails.
+type SegmentErrorPolicy string
+
+const (
+	// SegmentFailFast stops every segment worker as soon as a segment fails. The failure is returned by the next
+	// ParallelScanReader.GetItem call.
+	SegmentFailFast SegmentErrorPolicy = "FAIL_FAST"
+	// SegmentContinue keeps scanning the remaining segments if a segment fails. Failures are reported by
+	// ParallelScanReader.Errors once every segment has finished.
+	SegmentContinue SegmentErrorPolicy = "CONTINUE"
+)
+
+// SegmentError a failure of a single segment from a parallel scan.
+type SegmentError struct {
+	Segment int32
+	Err     error
+}
+
+var _ error = SegmentError{}
+
+func (e SegmentError) Error() string {
+	return "dynamoql: Segment " + strconv.Itoa(int(e.Segment)) + " failed: " + e.Err.Error()
+}
+
+// Unwrap retrieves the underlying error.
+func (e SegmentError) Unwrap() error {
+	return e.Err
+}
+
+// ParallelScanReader iterates for each item stored in an Amazon DynamoDB table using the Scan API in parallel.
+//
+// Starts a worker (goroutine) per segment (TotalSegments), each one paginating its own segment independently.
+// Items from every segment are merged into a single stream, hence no ordering is guaranteed.
+//
+// Workers are started on the first GetItem call and run on a context.Context owned by the reader, hence contexts
+// given to GetItem only bound each call. Use Close to stop workers before reaching the end of the stream.
+//
+// Some example for using ParallelScanReader:
+//
+//	defer r.Close()
+//	for r.Next() {
+//		item, err := r.GetItem(ctx)
+//		if err != nil {
+//			break
+//		}
+//		// ...
+//	}
+//	if errs := r.Errors(); len(errs) > 0 {
+//		// handle failed segments
+//	}
+type ParallelScanReader struct {
+	client        *dynamodb.Client
+	query         dynamodb.ScanInput
+	totalSegments int32
+	chunkSize     int32
+	policy        SegmentErrorPolicy
+	startOnce     sync.Once
+	cancel        context.CancelFunc
+	items         chan map[string]types.AttributeValue
+	mu            sync.Mutex
+	errs          []error
+	closed        bool
+	hasNext       bool
+	itemCount     int
+}
+
+// NewParallelScanReader allocates a ParallelScanReader with required internal components.
+//
+// Total segments are taken from dynamodb.ScanInput's TotalSegments, a single segment is scanned if not set.
+// The chunk size is used as page size for each segment and as merged stream buffer size.
+func NewParallelScanReader(chunkSize int32, c *dynamodb.Client, q dynamodb.ScanInput,
+	policy SegmentErrorPolicy) *ParallelScanReader {
+	totalSegments := int32(1)
+	if q.TotalSegments != nil && *q.TotalSegments > 0 {
+		totalSegments = *q.TotalSegments
+	}
+	if chunkSize > 0 {
+		q.Limit = &chunkSize
+	}
+	if policy == "" {
+		policy = SegmentFailFast
+	}
+	return &ParallelScanReader{
+		client:        c,
+		query:         q,
+		totalSegments: totalSegments,
+		chunkSize:     chunkSize,
+		policy:        policy,
+		hasNext:       true,
+	}
+}
+
+// Next indicates if there is another item to get.
+func (r *ParallelScanReader) Next() bool {
+	return r.hasNext
+}
+
+// Count returns the count of each item retrieved by a ParallelScanReader instance.
+func (r *ParallelScanReader) Count() int {
+	return r.itemCount
+}
+
+// Errors retrieves failures from segments (SegmentError).
+func (r *ParallelScanReader) Errors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.errs
+}
+
+// GetItem retrieves an Item from an Amazon DynamoDB table. Blocks until an item is available.
+//
+// Returns ErrReaderEOF if every segment has been scanned or the reader was closed.
+func (r *ParallelScanReader) GetItem(ctx context.Context) (map[string]types.AttributeValue, error) {
+	r.startOnce.Do(r.start)
+	if r.isClosed() || r.items == nil {
+		r.hasNext = false
+		return nil, ErrReaderEOF
+	}
+	if err := r.failure(); err != nil {
+		r.hasNext = false
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case item, ok := <-r.items:
+		if !ok {
+			r.hasNext = false
+			if err := r.failure(); err != nil {
+				return nil, err
+			}
+			return nil, ErrReaderEOF
+		}
+		r.itemCount++
+		return item, nil
+	}
+}
+
+// Close stops every segment worker.
+func (r *ParallelScanReader) Close() {
+	r.startOnce.Do(func() {})
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	r.hasNext = false
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+// isClosed indicates if Close was called.
+func (r *ParallelScanReader) isClosed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closed
+}
+
+// stopped indicates if workers were stopped on purpose, either by Close or by a segment failure if SegmentFailFast
+// policy is set.
+func (r *ParallelScanReader) stopped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closed || (r.policy == SegmentFailFast && len(r.errs) > 0)
+}
+
+// failure retrieves the first segment failure if SegmentFailFast policy is set.
+func (r *ParallelScanReader) failure() error {
+	if r.policy != SegmentFailFast {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.errs) == 0 {
+		return nil
+	}
+	return r.errs[0]
+}
+
+// start spawns a worker for each segment. Merged stream is closed once every worker has finished.
+//
+// Workers are bound to a context.Context owned by the reader, cancelled only by Close or a segment failure if
+// SegmentFailFast policy is set.
+func (r *ParallelScanReader) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	bufSize := int(r.chunkSize)
+	if bufSize < 0 {
+		bufSize = 0
+	}
+	r.items = make(chan map[string]types.AttributeValue, bufSize)
+	wg := sync.WaitGroup{}
+	wg.Add(int(r.totalSegments))
+	for i := int32(0); i < r.totalSegments; i++ {
+		go func(segment int32) {
+			defer wg.Done()
+			if err := r.scanSegment(ctx, segment); err != nil {
+				r.fail(segment, err)
+			}
+		}(i)
+	}
+	go func() {
+		wg.Wait()
+		close(r.items)
+	}()
+}
+
+// scanSegment paginates the given segment using a ScanPaginator, sending each item into the merged stream.
+//
+// Returns the context error if the segment was cut short for any other reason than the reader being stopped.
+func (r *ParallelScanReader) scanSegment(ctx context.Context, segment int32) error {
+	in := r.query
+	in.Segment = &segment
+	in.TotalSegments = &r.totalSegments
+	p := NewScanPaginator(r.chunkSize, r.client, in)
+	for p.Next() {
+		out, err := p.GetPage(ctx)
+		if err != nil && ctx.Err() != nil && r.stopped() {
+			// reader was either closed or stopped by another segment failure
+			return nil
+		} else if err != nil {
+			return err
+		}
+		for _, item := range out.Items {
+			select {
+			case <-ctx.Done():
+				if r.stopped() {
+					return nil
+				}
+				return ctx.Err()
+			case r.items <- item:
+			}
+		}
+	}
+	return nil
+}
+
+// fail registers a segment failure. Stops every worker if SegmentFailFast policy is set.
+func (r *ParallelScanReader) fail(segment int32, err error) {
+	r.mu.Lock()
+	r.errs = append(r.errs, SegmentError{
+		Segment: segment,
+		Err:     err,
+	})
+	r.mu.Unlock()
+	if r.policy == SegmentFailFast {
+		r.cancel()
+	}
+}
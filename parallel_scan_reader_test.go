@@ -0,0 +1,106 @@
+//go:build integration
+
+package dynamoql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type parallelScanReaderTestSuite struct {
+	suite.Suite
+
+	client *dynamodb.Client
+}
+
+func TestNewParallelScanReader(t *testing.T) {
+	suite.Run(t, &parallelScanReaderTestSuite{})
+}
+
+func (s *parallelScanReaderTestSuite) SetupSuite() {
+	s.client = newDynamoClient()
+}
+
+func (s *parallelScanReaderTestSuite) SetupTest() {}
+
+func (s *parallelScanReaderTestSuite) TearDownTest() {}
+
+func (s *parallelScanReaderTestSuite) TearDownSuite() {}
+
+func (s *parallelScanReaderTestSuite) TestParallelScanReader_GetItem() {
+	tests := []struct {
+		name     string
+		query    *dynamodb.ScanInput
+		segments int32
+		pageSize int32
+		expItems int
+		wantErr  bool
+	}{
+		{
+			name:     "Missing table",
+			query:    &dynamodb.ScanInput{},
+			segments: 4,
+			pageSize: 10,
+			expItems: 0,
+			wantErr:  true,
+		},
+		{
+			name: "Single segment",
+			query: func() *dynamodb.ScanInput {
+				in := mustNewScanInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+					Operator: dynamoql.Equals,
+					Field:    "SK",
+					Value:    "root",
+				}))
+				return &in
+			}(),
+			segments: 1,
+			pageSize: 100,
+			expItems: 780,
+		},
+		{
+			name: "Valid",
+			query: func() *dynamodb.ScanInput {
+				in := mustNewScanInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+					Operator: dynamoql.Equals,
+					Field:    "SK",
+					Value:    "root",
+				}))
+				return &in
+			}(),
+			segments: 8,
+			pageSize: 100,
+			expItems: 780,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			tt.query.TotalSegments = &tt.segments
+			r := dynamoql.NewParallelScanReader(tt.pageSize, s.client, *tt.query, dynamoql.SegmentFailFast)
+			defer r.Close()
+
+			ctx := context.Background()
+			keys := make(map[string]struct{}, tt.expItems)
+			for r.Next() {
+				item, err := r.GetItem(ctx)
+				if err == dynamoql.ErrReaderEOF {
+					break
+				}
+				require.Equal(t, tt.wantErr, err != nil)
+				if err != nil {
+					break
+				}
+				keys[dynamoql.MustParseString(item["PK"])] = struct{}{}
+			}
+			assert.Equal(t, tt.expItems, r.Count())
+			assert.Len(t, keys, tt.expItems)
+			assert.Equal(t, tt.wantErr, len(r.Errors()) > 0)
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package dynamoql
 
 import (
+	"encoding"
 	"errors"
+	"math/big"
+	"reflect"
 	"strconv"
 	"time"
 	"unsafe"
@@ -15,11 +18,58 @@ var DefaultTimeFormat = time.RFC3339
 // ErrCannotCastAttribute casting Amazon DynamoDB attribute failed.
 var ErrCannotCastAttribute = errors.New("dynamoql: Cannot cast attribute")
 
-// FormatAttribute converts a Go primitive type into a DynamoDB type.
+// ErrEmptySet the given set holds no elements, Amazon DynamoDB does not accept empty sets.
+var ErrEmptySet = errors.New("dynamoql: Empty set")
+
+// FormatAttribute converts a Go value into a DynamoDB type. See MarshalAttribute for supported values.
 //
-// Returns nil if unknown value is received.
+// Returns nil if unknown value is received, use MarshalAttribute to retrieve the failure instead.
 func FormatAttribute(v interface{}) types.AttributeValue {
+	attr, _ := MarshalAttribute(v)
+	return attr
+}
+
+// MarshalAttribute converts a Go value into a DynamoDB type.
+//
+// Amazon DynamoDB attributes (types.AttributeValue) are returned as is. Besides primitive types (and slices of them,
+// formatted as sets), the following values are supported:
+//
+//   - time.Time is formatted using DefaultTimeFormat, use FormatTime to apply another TimeEncoding.
+//   - time.Duration is converted into N (nanoseconds).
+//   - Decimal, *big.Int, *big.Float and *big.Rat are converted into N, validated against Amazon DynamoDB number
+//     limits (see ValidateNumber). Empty Decimal values are converted into NULL.
+//   - nil and nil pointers, maps or slices (including sets and []byte) are converted into NULL.
+//   - Empty sets return ErrEmptySet.
+//   - Pointers are dereferenced.
+//   - Values implementing Marshaler are converted into M.
+//   - Values implementing encoding.TextMarshaler are converted into S.
+//   - Maps with string keys are converted into M.
+//   - Slices and arrays of any other type are converted into L.
+//
+// Returns ErrUnsupportedType (wrapped into an AttributeError for nested values) if v, or any of its elements, cannot
+// be converted. Numbers exceeding Amazon DynamoDB limits return either ErrInvalidNumber, ErrNumberPrecision or
+// ErrNumberOutOfRange.
+func MarshalAttribute(v interface{}) (types.AttributeValue, error) {
+	switch v.(type) {
+	case []string, []int, []uint, []float32, []float64, [][]byte, []byte:
+		// primitive slices are formatted as sets (or binary) by formatPrimitive
+		if rv := reflect.ValueOf(v); rv.IsNil() {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		} else if rv.Len() == 0 && rv.Type() != byteSliceType {
+			return nil, ErrEmptySet
+		}
+	}
+	if attr := formatPrimitive(v); attr != nil {
+		return attr, nil
+	}
+	return marshalValue(v)
+}
+
+// formatPrimitive converts a Go primitive type into a DynamoDB type. Returns nil if v is not a primitive type.
+func formatPrimitive(v interface{}) types.AttributeValue {
 	switch v.(type) {
+	case types.AttributeValue:
+		return v.(types.AttributeValue)
 	case string:
 		val := v.(string)
 		return &types.AttributeValueMemberS{Value: val}
@@ -60,7 +110,7 @@ func FormatAttribute(v interface{}) types.AttributeValue {
 		return &types.AttributeValueMemberN{Value: strconv.Itoa(int(val))}
 	case int64:
 		val := v.(int64)
-		return &types.AttributeValueMemberN{Value: strconv.Itoa(int(val))}
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(val, 10)}
 	case []int:
 		val := v.([]int)
 		buf := make([]string, 0, len(val))
@@ -99,6 +149,9 @@ func FormatAttribute(v interface{}) types.AttributeValue {
 	case bool:
 		val := v.(bool)
 		return &types.AttributeValueMemberBOOL{Value: val}
+	case time.Duration:
+		val := v.(time.Duration)
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(int64(val), 10)}
 	case time.Time:
 		val := v.(time.Time)
 		var a [64]byte
@@ -109,6 +162,63 @@ func FormatAttribute(v interface{}) types.AttributeValue {
 	return nil
 }
 
+// marshalValue converts non-primitive Go values into a DynamoDB type.
+func marshalValue(v interface{}) (types.AttributeValue, error) {
+	if v == nil {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		}
+	}
+	switch val := v.(type) {
+	case Decimal, *big.Int, *big.Float, *big.Rat:
+		return formatNumberValue(val)
+	case Marshaler:
+		return encodeMarshaler(val)
+	case encoding.TextMarshaler:
+		text, err := val.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberS{Value: string(text)}, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		return MarshalAttribute(rv.Elem().Interface())
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, ErrUnsupportedType
+		}
+		m := make(map[string]types.AttributeValue, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			attr, err := MarshalAttribute(iter.Value().Interface())
+			if err != nil {
+				return nil, AttributeError{Attribute: iter.Key().String(), Err: err}
+			}
+			m[iter.Key().String()] = attr
+		}
+		return &types.AttributeValueMemberM{Value: m}, nil
+	case reflect.Slice, reflect.Array:
+		list := make([]types.AttributeValue, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			attr, err := MarshalAttribute(rv.Index(i).Interface())
+			if err != nil {
+				return nil, AttributeError{Attribute: "[" + strconv.Itoa(i) + "]", Err: err}
+			}
+			list = append(list, attr)
+		}
+		return &types.AttributeValueMemberL{Value: list}, nil
+	default:
+		return nil, ErrUnsupportedType
+	}
+}
+
 // ParseString converts the given Amazon DynamoDB attribute into string.
 func ParseString(v types.AttributeValue) (string, error) {
 	data, ok := v.(*types.AttributeValueMemberS)
@@ -119,12 +229,38 @@ func ParseString(v types.AttributeValue) (string, error) {
 }
 
 // ParseTime converts the given Amazon DynamoDB attribute into time.Time.
+//
+// Strings (S) are parsed using DefaultTimeFormat while numbers (N) are parsed as Unix epoch values (UTC), either
+// seconds or milliseconds depending on their magnitude. Use ParseTimeAs to parse a specific TimeEncoding.
 func ParseTime(v types.AttributeValue) (time.Time, error) {
-	data, ok := v.(*types.AttributeValueMemberS)
+	data, ok := v.(*types.AttributeValueMemberN)
 	if !ok {
-		return time.Time{}, ErrCannotCastAttribute
+		return ParseTimeAs(v, "")
+	}
+	n, err := strconv.ParseInt(data.Value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	} else if n >= unixMilliThreshold || n <= -unixMilliThreshold {
+		return time.UnixMilli(n).UTC(), nil
+	}
+	return time.Unix(n, 0).UTC(), nil
+}
+
+// ParseDuration converts the given Amazon DynamoDB attribute into time.Duration. Numbers (N) are parsed as
+// nanoseconds while strings (S) are parsed using time.ParseDuration (e.g. 1h30m).
+func ParseDuration(v types.AttributeValue) (time.Duration, error) {
+	switch data := v.(type) {
+	case *types.AttributeValueMemberN:
+		n, err := strconv.ParseInt(data.Value, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n), nil
+	case *types.AttributeValueMemberS:
+		return time.ParseDuration(data.Value)
+	default:
+		return 0, ErrCannotCastAttribute
 	}
-	return time.Parse(DefaultTimeFormat, data.Value)
 }
 
 // ParseFloat64 converts the given Amazon DynamoDB attribute into 64-bit floating point.
@@ -371,6 +507,31 @@ func ParseBool(v types.AttributeValue) (bool, error) {
 	return data.Value, nil
 }
 
+// ParseMap converts the given Amazon DynamoDB attribute into a map of attributes.
+func ParseMap(v types.AttributeValue) (map[string]types.AttributeValue, error) {
+	data, ok := v.(*types.AttributeValueMemberM)
+	if !ok {
+		return nil, ErrCannotCastAttribute
+	}
+	return data.Value, nil
+}
+
+// ParseList converts the given Amazon DynamoDB attribute into a list of attributes.
+func ParseList(v types.AttributeValue) ([]types.AttributeValue, error) {
+	data, ok := v.(*types.AttributeValueMemberL)
+	if !ok {
+		return nil, ErrCannotCastAttribute
+	}
+	return data.Value, nil
+}
+
+// IsNull indicates if the given Amazon DynamoDB attribute is a NULL attribute. Returns false if v is nil (e.g. a
+// missing attribute).
+func IsNull(v types.AttributeValue) bool {
+	data, ok := v.(*types.AttributeValueMemberNULL)
+	return ok && data.Value
+}
+
 // MustParseString converts the given Amazon DynamoDB attribute into string.
 //
 // If fails to parse attribute, returns nil or zero-value.
@@ -386,17 +547,24 @@ func MustParseString(v types.AttributeValue) string {
 //
 // If fails to parse attribute, returns nil or zero-value.
 func MustParseTime(v types.AttributeValue) time.Time {
-	data, ok := v.(*types.AttributeValueMemberS)
-	if !ok {
-		return time.Time{}
-	}
-	t, err := time.Parse(DefaultTimeFormat, data.Value)
+	t, err := ParseTime(v)
 	if err != nil {
 		return time.Time{}
 	}
 	return t
 }
 
+// MustParseDuration converts the given Amazon DynamoDB attribute into time.Duration.
+//
+// If fails to parse attribute, returns nil or zero-value.
+func MustParseDuration(v types.AttributeValue) time.Duration {
+	d, err := ParseDuration(v)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
 // MustParseFloat64 converts the given Amazon DynamoDB attribute into 64-bit floating point.
 //
 // If fails to parse attribute, returns nil or zero-value.
@@ -696,3 +864,25 @@ func MustParseBool(v types.AttributeValue) bool {
 	}
 	return data.Value
 }
+
+// MustParseMap converts the given Amazon DynamoDB attribute into a map of attributes.
+//
+// If fails to parse attribute, returns nil or zero-value.
+func MustParseMap(v types.AttributeValue) map[string]types.AttributeValue {
+	data, ok := v.(*types.AttributeValueMemberM)
+	if !ok {
+		return nil
+	}
+	return data.Value
+}
+
+// MustParseList converts the given Amazon DynamoDB attribute into a list of attributes.
+//
+// If fails to parse attribute, returns nil or zero-value.
+func MustParseList(v types.AttributeValue) []types.AttributeValue {
+	data, ok := v.(*types.AttributeValueMemberL)
+	if !ok {
+		return nil
+	}
+	return data.Value
+}
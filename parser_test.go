@@ -1,12 +1,15 @@
 package dynamoql_test
 
 import (
+	"net"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/maestre3d/dynamoql-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatAttribute(t *testing.T) {
@@ -20,7 +23,55 @@ func TestFormatAttribute(t *testing.T) {
 		{
 			Name: "Empty",
 			In:   nil,
-			Exp:  nil,
+			Exp:  &types.AttributeValueMemberNULL{Value: true},
+		},
+		{
+			Name: "Nil pointer",
+			In:   (*string)(nil),
+			Exp:  &types.AttributeValueMemberNULL{Value: true},
+		},
+		{
+			Name: "Pointer",
+			In:   aws.String("foo"),
+			Exp:  &types.AttributeValueMemberS{Value: "foo"},
+		},
+		{
+			Name: "Map",
+			In:   map[string]interface{}{"foo": "bar", "baz": nil, "count": 1},
+			Exp: &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"foo":   &types.AttributeValueMemberS{Value: "bar"},
+				"baz":   &types.AttributeValueMemberNULL{Value: true},
+				"count": &types.AttributeValueMemberN{Value: "1"},
+			}},
+		},
+		{
+			Name: "List",
+			In:   []interface{}{"foo", 1, []string{"bar"}},
+			Exp: &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "foo"},
+				&types.AttributeValueMemberN{Value: "1"},
+				&types.AttributeValueMemberSS{Value: []string{"bar"}},
+			}},
+		},
+		{
+			Name: "Marshaler",
+			In:   Bill{InvoiceID: "1", BillID: "2"},
+			Exp: &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"PK":          &types.AttributeValueMemberS{Value: "I#1"},
+				"SK":          &types.AttributeValueMemberS{Value: "B#2"},
+				"BillAmount":  &types.AttributeValueMemberS{Value: ""},
+				"BillBalance": &types.AttributeValueMemberS{Value: ""},
+			}},
+		},
+		{
+			Name: "Duration",
+			In:   time.Minute,
+			Exp:  &types.AttributeValueMemberN{Value: "60000000000"},
+		},
+		{
+			Name: "Text marshaler",
+			In:   net.ParseIP("127.0.0.1"),
+			Exp:  &types.AttributeValueMemberS{Value: "127.0.0.1"},
 		},
 		{
 			Name: "Arbitrary type",
@@ -190,6 +241,44 @@ func TestFormatAttribute(t *testing.T) {
 	}
 }
 
+func TestMarshalAttribute(t *testing.T) {
+	attr, err := dynamoql.MarshalAttribute(map[string][]string{"tags": {"foo"}})
+	require.NoError(t, err)
+	assert.Equal(t, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"tags": &types.AttributeValueMemberSS{Value: []string{"foo"}},
+	}}, attr)
+
+	_, err = dynamoql.MarshalAttribute(make(chan int))
+	assert.ErrorIs(t, err, dynamoql.ErrUnsupportedType)
+	_, err = dynamoql.MarshalAttribute(map[int]string{1: "foo"})
+	assert.ErrorIs(t, err, dynamoql.ErrUnsupportedType)
+	_, err = dynamoql.MarshalAttribute(map[string]interface{}{
+		"items": []interface{}{"foo", struct{}{}},
+	})
+	assert.ErrorIs(t, err, dynamoql.ErrUnsupportedType)
+	assert.EqualError(t, err, `dynamoql: Attribute "items": dynamoql: Attribute "[1]": dynamoql: Unsupported type`)
+	assert.Nil(t, dynamoql.FormatAttribute(struct{}{}))
+}
+
+func TestMarshalAttribute_Sets(t *testing.T) {
+	null := &types.AttributeValueMemberNULL{Value: true}
+	for _, v := range []interface{}{[]string(nil), []int(nil), []float64(nil), [][]byte(nil), []byte(nil)} {
+		attr, err := dynamoql.MarshalAttribute(v)
+		require.NoError(t, err)
+		assert.Equal(t, null, attr)
+	}
+	for _, v := range []interface{}{[]string{}, []int{}, []uint{}, []float32{}, []float64{}, [][]byte{}} {
+		_, err := dynamoql.MarshalAttribute(v)
+		assert.ErrorIs(t, err, dynamoql.ErrEmptySet)
+	}
+	attr, err := dynamoql.MarshalAttribute([]byte{})
+	require.NoError(t, err)
+	assert.Equal(t, &types.AttributeValueMemberB{Value: []byte{}}, attr)
+
+	_, err = dynamoql.MarshalAttribute(map[string]interface{}{"tags": []string{}})
+	assert.ErrorIs(t, err, dynamoql.ErrEmptySet)
+}
+
 func TestParseBinary(t *testing.T) {
 	exp := []byte{0, 1, 0}
 	out, err := dynamoql.ParseBinary(&types.AttributeValueMemberB{
@@ -211,6 +300,29 @@ func TestParseTime(t *testing.T) {
 	out, err := dynamoql.ParseTime(&types.AttributeValueMemberS{Value: "1990-12-31T12:45:30.00000004Z"})
 	assert.NoError(t, err)
 	assert.Equal(t, exp, out)
+
+	exp = time.Date(1990, 12, 31, 12, 45, 30, 0, time.UTC)
+	out, err = dynamoql.ParseTime(&types.AttributeValueMemberN{Value: "662647530"})
+	assert.NoError(t, err)
+	assert.Equal(t, exp, out)
+	out, err = dynamoql.ParseTime(&types.AttributeValueMemberN{Value: "662647530250"})
+	assert.NoError(t, err)
+	assert.Equal(t, exp.Add(250*time.Millisecond), out)
+	_, err = dynamoql.ParseTime(dynamoql.FormatAttribute(true))
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+}
+
+func TestParseDuration(t *testing.T) {
+	out, err := dynamoql.ParseDuration(dynamoql.FormatAttribute(90 * time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, out)
+	out, err = dynamoql.ParseDuration(dynamoql.FormatAttribute("1h30m"))
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, out)
+	_, err = dynamoql.ParseDuration(dynamoql.FormatAttribute(true))
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+	assert.Equal(t, time.Second, dynamoql.MustParseDuration(dynamoql.FormatAttribute("1s")))
+	assert.Zero(t, dynamoql.MustParseDuration(dynamoql.FormatAttribute("foo")))
 }
 
 func TestParseBinarySet(t *testing.T) {
@@ -383,6 +495,35 @@ func TestParseUintSet(t *testing.T) {
 	assert.Equal(t, exp, out)
 }
 
+func TestParseMap(t *testing.T) {
+	m := map[string]types.AttributeValue{"foo": dynamoql.FormatAttribute("bar")}
+	out, err := dynamoql.ParseMap(&types.AttributeValueMemberM{Value: m})
+	assert.NoError(t, err)
+	assert.Equal(t, m, out)
+	_, err = dynamoql.ParseMap(dynamoql.FormatAttribute("foo"))
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+	assert.Equal(t, m, dynamoql.MustParseMap(&types.AttributeValueMemberM{Value: m}))
+	assert.Nil(t, dynamoql.MustParseMap(nil))
+}
+
+func TestParseList(t *testing.T) {
+	l := []types.AttributeValue{dynamoql.FormatAttribute("bar")}
+	out, err := dynamoql.ParseList(&types.AttributeValueMemberL{Value: l})
+	assert.NoError(t, err)
+	assert.Equal(t, l, out)
+	_, err = dynamoql.ParseList(dynamoql.FormatAttribute("foo"))
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+	assert.Equal(t, l, dynamoql.MustParseList(&types.AttributeValueMemberL{Value: l}))
+	assert.Nil(t, dynamoql.MustParseList(nil))
+}
+
+func TestIsNull(t *testing.T) {
+	assert.True(t, dynamoql.IsNull(dynamoql.FormatAttribute(nil)))
+	assert.False(t, dynamoql.IsNull(&types.AttributeValueMemberNULL{Value: false}))
+	assert.False(t, dynamoql.IsNull(dynamoql.FormatAttribute("foo")))
+	assert.False(t, dynamoql.IsNull(nil))
+}
+
 func TestMustParseString(t *testing.T) {
 	exp := "foo"
 	out := dynamoql.MustParseString(&types.AttributeValueMemberS{Value: "foo"})
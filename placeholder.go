@@ -0,0 +1,95 @@
+package dynamoql
+
+import (
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	placeholderNamePrefix  = "#n"
+	placeholderValuePrefix = ":v"
+)
+
+// placeholderAllocator assigns unique tokens to each operand of an Amazon DynamoDB expression.
+//
+// Attribute names are mapped to #n0, #n1, ... #nN tokens while attribute values are mapped to :v0, :v1, ... :vN
+// tokens. As tokens are never derived from attribute names, they are always valid no matter the characters used by
+// the actual attribute name (e.g. foo-bar, foo.bar or foo bar).
+//
+// A single allocator MUST be shared by every expression of a request (e.g. key, filter and projection expressions)
+// to avoid token collisions.
+type placeholderAllocator struct {
+	names      map[string]string
+	nameTokens map[string]string
+	values     map[string]types.AttributeValue
+	valueCount int
+}
+
+// newPlaceholderAllocator allocates a placeholderAllocator.
+func newPlaceholderAllocator() *placeholderAllocator {
+	return &placeholderAllocator{}
+}
+
+// name retrieves the token of the given attribute name. Tokens are reused if the same attribute name was already
+// allocated.
+func (a *placeholderAllocator) name(n string) string {
+	if tok, ok := a.nameTokens[n]; ok {
+		return tok
+	}
+	if a.names == nil {
+		a.names = make(map[string]string)
+		a.nameTokens = make(map[string]string)
+	}
+	tok := placeholderNamePrefix + strconv.Itoa(len(a.names))
+	a.names[tok] = n
+	a.nameTokens[n] = tok
+	return tok
+}
+
+// value retrieves a new token for the given attribute value. Unlike attribute names, tokens are never reused.
+//
+// Nil values are stored as NULL. Returns the MarshalAttribute error if v cannot be converted into an Amazon DynamoDB
+// attribute, no token is allocated then.
+func (a *placeholderAllocator) value(v interface{}) (string, error) {
+	attr, err := MarshalAttribute(v)
+	if err != nil {
+		return "", err
+	}
+	if a.values == nil {
+		a.values = make(map[string]types.AttributeValue)
+	}
+	tok := placeholderValuePrefix + strconv.Itoa(a.valueCount)
+	a.valueCount++
+	a.values[tok] = attr
+	return tok, nil
+}
+
+// operand retrieves the token of the given right-hand operand. FieldRef operands are allocated as attribute names
+// (see path) while any other value is allocated as attribute value.
+func (a *placeholderAllocator) operand(v interface{}) (string, error) {
+	if ref, ok := v.(FieldRef); ok {
+		return a.path(string(ref)), nil
+	}
+	return a.value(v)
+}
+
+// attributeNames retrieves allocated attribute names (ExpressionAttributeNames).
+//
+// Returns nil if no names were allocated.
+func (a *placeholderAllocator) attributeNames() map[string]string {
+	if len(a.names) == 0 {
+		return nil
+	}
+	return a.names
+}
+
+// attributeValues retrieves allocated attribute values (ExpressionAttributeValues).
+//
+// Returns nil if no values were allocated.
+func (a *placeholderAllocator) attributeValues() map[string]types.AttributeValue {
+	if len(a.values) == 0 {
+		return nil
+	}
+	return a.values
+}
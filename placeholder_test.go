@@ -0,0 +1,41 @@
+package dynamoql
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceholderAllocator(t *testing.T) {
+	a := newPlaceholderAllocator()
+	assert.Nil(t, a.attributeNames())
+	assert.Nil(t, a.attributeValues())
+
+	assert.Equal(t, "#n0", a.name("sort_key"))
+	assert.Equal(t, "#n1", a.name("foo-bar"))
+	assert.Equal(t, "#n2", a.name("foo bar"))
+	assert.Equal(t, "#n0", a.name("sort_key")) // reused
+	v, err := a.value("foo")
+	require.NoError(t, err)
+	assert.Equal(t, ":v0", v)
+	v, err = a.value("foo")
+	require.NoError(t, err)
+	assert.Equal(t, ":v1", v) // never reused
+	_, err = a.value(struct{}{})
+	assert.ErrorIs(t, err, ErrUnsupportedType) // no placeholder allocated
+	v, err = a.value(nil)
+	require.NoError(t, err)
+	assert.Equal(t, ":v2", v)
+	assert.Equal(t, map[string]string{
+		"#n0": "sort_key",
+		"#n1": "foo-bar",
+		"#n2": "foo bar",
+	}, a.attributeNames())
+	assert.Equal(t, map[string]types.AttributeValue{
+		":v0": &types.AttributeValueMemberS{Value: "foo"},
+		":v1": &types.AttributeValueMemberS{Value: "foo"},
+		":v2": &types.AttributeValueMemberNULL{Value: true},
+	}, a.attributeValues())
+}
@@ -0,0 +1,147 @@
+package dynamoql
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// PutBuilder crafts an Amazon DynamoDB put statement ready to be used by PutItem API.
+//
+// Conditions (Where) are written as the condition expression of the put, hence the item is written only if the
+// existing item (if any) matches them.
+//
+// e.g. dynamoql.Put(student).Table("Graph").Where(dynamoql.Condition{
+// Operator: dynamoql.AttributeNotExists, Field: "partition_key"})
+type PutBuilder struct {
+	negate        bool
+	operator      LogicalOperator
+	table         string
+	schema        Marshaler
+	conditions    []Condition
+	returnValues  types.ReturnValue
+	returnMetrics types.ReturnConsumedCapacity
+}
+
+// NewPutBuilder builds a PutBuilder instance.
+func NewPutBuilder() *PutBuilder {
+	return &PutBuilder{
+		returnValues:  types.ReturnValueNone,
+		returnMetrics: types.ReturnConsumedCapacityNone,
+	}
+}
+
+// Put builds a new PutBuilder instance and sets the schema to be written.
+func Put(schema Marshaler) *PutBuilder {
+	return NewPutBuilder().Schema(schema)
+}
+
+// Schema sets the schema to be written. The schema is marshaled when the PutItem API input is built.
+func (p *PutBuilder) Schema(schema Marshaler) *PutBuilder {
+	p.schema = schema
+	return p
+}
+
+// Table sets the table to write the item into.
+func (p *PutBuilder) Table(table string) *PutBuilder {
+	p.table = table
+	return p
+}
+
+// Where sets conditions statements. The item is written only if the existing item matches them.
+func (p *PutBuilder) Where(c ...Condition) *PutBuilder {
+	p.conditions = c
+	return p
+}
+
+// And concatenates Condition(s) with an And operator.
+func (p *PutBuilder) And() *PutBuilder {
+	p.operator = And
+	return p
+}
+
+// Or concatenates Condition(s) with an Or operator.
+func (p *PutBuilder) Or() *PutBuilder {
+	p.operator = Or
+	return p
+}
+
+// Negate sets the condition statements output to be opposite.
+func (p *PutBuilder) Negate() *PutBuilder {
+	p.negate = true
+	return p
+}
+
+// ReturnValues sets the item attributes to be returned by the put operation. Accepts either
+// types.ReturnValueNone or types.ReturnValueAllOld.
+func (p *PutBuilder) ReturnValues(v types.ReturnValue) *PutBuilder {
+	p.returnValues = v
+	return p
+}
+
+// Metrics sets the desired metric data from consumed capacity outputs.
+func (p *PutBuilder) Metrics(v types.ReturnConsumedCapacity) *PutBuilder {
+	p.returnMetrics = v
+	return p
+}
+
+// Validate verifies the current PutBuilder instance values are accepted by PutItem API, returning a ValidationError
+// if not.
+func (p *PutBuilder) Validate() error {
+	if p.table == "" {
+		return newValidationError(ErrMissingTable, "", "table name is required (Table)")
+	} else if p.schema == nil {
+		return newValidationError(ErrMissingSchema, "", "schema is required (Schema)")
+	} else if err := validateWriteReturnValues(p.returnValues); err != nil {
+		return err
+	}
+	return validateWriteConditions(p.operator, p.conditions)
+}
+
+// NewPutInput builds a dynamodb.PutItemInput using current PutBuilder instance values.
+//
+// Returns a ValidationError if PutBuilder values are not accepted by PutItem API or the error returned by the schema
+// Marshaler.
+func NewPutInput(p *PutBuilder) (dynamodb.PutItemInput, error) {
+	if err := p.Validate(); err != nil {
+		return dynamodb.PutItemInput{}, err
+	}
+	item, err := p.schema.MarshalDynamoDB()
+	if err != nil {
+		return dynamodb.PutItemInput{}, err
+	} else if len(item) == 0 {
+		return dynamodb.PutItemInput{}, newValidationError(ErrMissingKeys, "",
+			"schema marshaled into an empty item, primary key attributes are required")
+	}
+	a := newPlaceholderAllocator()
+	conditionExpr, err := buildExpression(a, p.operator, p.negate, p.conditions)
+	if err != nil {
+		return dynamodb.PutItemInput{}, err
+	}
+	return dynamodb.PutItemInput{
+		Item:                      item,
+		TableName:                 &p.table,
+		ConditionExpression:       conditionExpr,
+		ExpressionAttributeNames:  a.attributeNames(),
+		ExpressionAttributeValues: a.attributeValues(),
+		ReturnConsumedCapacity:    p.returnMetrics,
+		ReturnValues:              p.returnValues,
+	}, nil
+}
+
+// ExecPut executes a PutItem API operation.
+//
+// Returns a ValidationError if PutBuilder values are not accepted by PutItem API and ErrConditionalCheckFailed if
+// the existing item does not match the conditions.
+func (p *PutBuilder) ExecPut(ctx context.Context, c *dynamodb.Client) (dynamodb.PutItemOutput, error) {
+	in, err := NewPutInput(p)
+	if err != nil {
+		return dynamodb.PutItemOutput{}, err
+	}
+	out, err := c.PutItem(ctx, &in)
+	if err != nil {
+		return dynamodb.PutItemOutput{}, newWriteError(err)
+	}
+	return *out, nil
+}
@@ -0,0 +1,104 @@
+package dynamoql_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type emptySchema struct{}
+
+func (s emptySchema) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
+	return nil, nil
+}
+
+func TestNewPutInput(t *testing.T) {
+	bill := Bill{InvoiceID: "1191", BillID: "1", Amount: "100", Balance: "0"}
+	item, _ := bill.MarshalDynamoDB()
+	in, err := dynamoql.NewPutInput(dynamoql.Put(bill).
+		Table("InvoiceAndBills").
+		Or().
+		Where(dynamoql.Condition{
+			Operator: dynamoql.AttributeNotExists,
+			Field:    "PK",
+		}, dynamoql.Condition{
+			Operator: dynamoql.LessThan,
+			Field:    "BillBalance",
+			Value:    dynamoql.FieldRef("BillAmount"),
+		}).
+		ReturnValues(types.ReturnValueAllOld).
+		Metrics(types.ReturnConsumedCapacityTotal))
+	require.NoError(t, err)
+	assert.Equal(t, item, in.Item)
+	assert.Equal(t, "InvoiceAndBills", *in.TableName)
+	assert.Equal(t, "attribute_not_exists(#n0) OR #n1 < #n2", *in.ConditionExpression)
+	assert.Equal(t, map[string]string{
+		"#n0": "PK",
+		"#n1": "BillBalance",
+		"#n2": "BillAmount",
+	}, in.ExpressionAttributeNames)
+	assert.Nil(t, in.ExpressionAttributeValues)
+	assert.Equal(t, types.ReturnValueAllOld, in.ReturnValues)
+	assert.Equal(t, types.ReturnConsumedCapacityTotal, in.ReturnConsumedCapacity)
+
+	in, err = dynamoql.NewPutInput(dynamoql.Put(bill).Table("InvoiceAndBills"))
+	require.NoError(t, err)
+	assert.Nil(t, in.ConditionExpression)
+	assert.Nil(t, in.ExpressionAttributeNames)
+	assert.Equal(t, types.ReturnValueNone, in.ReturnValues)
+}
+
+func TestPutBuilder_Validate(t *testing.T) {
+	bill := Bill{InvoiceID: "1191", BillID: "1"}
+	tests := []struct {
+		name string
+		put  *dynamoql.PutBuilder
+		exp  error
+	}{
+		{
+			name: "Missing table",
+			put:  dynamoql.Put(bill),
+			exp:  dynamoql.ErrMissingTable,
+		},
+		{
+			name: "Missing schema",
+			put:  dynamoql.NewPutBuilder().Table("sample"),
+			exp:  dynamoql.ErrMissingSchema,
+		},
+		{
+			name: "Empty item",
+			put:  dynamoql.Put(emptySchema{}).Table("sample"),
+			exp:  dynamoql.ErrMissingKeys,
+		},
+		{
+			name: "Invalid return values",
+			put:  dynamoql.Put(bill).Table("sample").ReturnValues(types.ReturnValueUpdatedNew),
+			exp:  dynamoql.ErrInvalidOption,
+		},
+		{
+			name: "Invalid condition",
+			put: dynamoql.Put(bill).Table("sample").Where(dynamoql.Condition{
+				Operator: dynamoql.Between,
+				Field:    "BillAmount",
+				Value:    1,
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Valid",
+			put: dynamoql.Put(bill).Table("sample").Where(dynamoql.Condition{
+				Operator: dynamoql.AttributeNotExists,
+				Field:    "PK",
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dynamoql.NewPutInput(tt.put)
+			assert.ErrorIs(t, err, tt.exp)
+		})
+	}
+}
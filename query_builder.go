@@ -2,15 +2,17 @@ package dynamoql
 
 import (
 	"context"
-	"strings"
+	"errors"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 var DefaultQueryLimit int32 = 10
 
+// ErrItemNotFound the requested item does not exist.
+var ErrItemNotFound = errors.New("dynamoql: Item not found")
+
 // Condition a comparison statement used by queries (QueryBuilder).
 type Condition struct {
 	// Negate sets an opposite value of the statement original output.
@@ -20,33 +22,70 @@ type Condition struct {
 	// Operator statement comparison operator.
 	Operator ConditionalOperator
 	// SecondaryOperator an additional comparison operator.
-	// Used by Size operator only.
+	// Used by Size operator only, accepts comparators, Between and In operators.
 	SecondaryOperator ConditionalOperator
-	// Field attribute name used by the comparison statement.
+	// Field attribute name or document path (e.g. Path("address.city"), Path("items[2].sku")) used by the comparison
+	// statement.
 	Field string
 	// Value attribute actual value used by the comparison statement.
+	//
+	// Use FieldRef to compare against another attribute (e.g. balance < credit_limit).
 	Value interface{}
 	// ExtraValues additional attribute values.
-	// Used by Between and In operators only
+	// Used by Between and In operators only (either as Operator or as SecondaryOperator of Size).
 	ExtraValues []interface{}
+	// Group nested statements evaluated as a single parenthesised statement.
+	//
+	// If set, Operator, SecondaryOperator, Field, Value and ExtraValues are ignored. Negate applies to the whole group.
+	Group *ConditionGroup
+}
+
+// FieldRef an attribute name or document path (Path) used as right-hand operand of a Condition, comparing two
+// attributes instead of an attribute and a literal value (e.g. updated_at > created_at).
+//
+// Might be used as Condition.Value or as any of Condition.ExtraValues.
+type FieldRef string
+
+// ConditionGroup a set of Condition(s) concatenated by the same LogicalOperator. A group might contain other groups,
+// forming a condition tree.
+//
+// e.g. status = :s AND (amount > :a OR contains(tags, :t))
+type ConditionGroup struct {
+	// Operator logical operator used to concatenate Conditions. Default is And.
+	Operator LogicalOperator
+	// Conditions nested statements of the group.
+	Conditions []Condition
+}
+
+// Group builds a Condition holding a ConditionGroup of the given Condition(s) concatenated with the given operator.
+//
+// Key conditions DO NOT accept groups; if the returned Condition is set as key, nested conditions are flattened and
+// concatenated with an And operator.
+func Group(op LogicalOperator, c ...Condition) Condition {
+	return Condition{
+		Group: &ConditionGroup{
+			Operator:   op,
+			Conditions: c,
+		},
+	}
 }
 
 // QueryBuilder crafts an Amazon DynamoDB query statement ready to be used by Query, GetItem and Scan APIs.
 //
 // Moreover, it integrates with Paginator and Reader instances for seamless API usability.
 type QueryBuilder struct {
-	isConsistent              bool
-	negate                    bool
-	limit                     int32
-	operator                  LogicalOperator
-	ordering                  Ordering
-	table                     string
-	index                     *string
-	projectedFieldsExpression *string
-	returnMetrics             types.ReturnConsumedCapacity
-	conditions                []Condition
-	pageToken                 PageToken
-	parallelDegree            int32
+	isConsistent    bool
+	negate          bool
+	limit           int32
+	operator        LogicalOperator
+	ordering        Ordering
+	table           string
+	index           *string
+	projectedFields []string
+	returnMetrics   types.ReturnConsumedCapacity
+	conditions      []Condition
+	pageToken       PageToken
+	parallelDegree  int32
 }
 
 // NewQueryBuilder builds a QueryBuilder instance.
@@ -64,10 +103,11 @@ func Select(projectedFields ...string) *QueryBuilder {
 	return NewQueryBuilder().Select(projectedFields)
 }
 
-// Select sets attributes to be projected by the query.
+// Select sets attributes to be projected by the query. Accepts attribute names and document paths
+// (e.g. Path("address.city"), Path("items[2].sku")).
 func (q *QueryBuilder) Select(projectedFields []string) *QueryBuilder {
 	if projectedFields != nil && len(projectedFields) > 0 {
-		q.projectedFieldsExpression = aws.String(strings.Join(projectedFields, ","))
+		q.projectedFields = projectedFields
 	}
 	return q
 }
@@ -141,27 +181,82 @@ func (q *QueryBuilder) Metrics(v types.ReturnConsumedCapacity) *QueryBuilder {
 	return q
 }
 
-// DegreeOfParallelism sets the number of segments to be scanned in parallel.
+// DegreeOfParallelism sets the number of segments to be scanned in parallel by GetParallelScanReader.
 //
-// Note: Only available for Scan operations.
+// Note: Only available for Scan operations. Sequential scans (e.g. ExecScan or GetScanReader) scan the whole table.
 func (q *QueryBuilder) DegreeOfParallelism(d int32) *QueryBuilder {
 	q.parallelDegree = d
 	return q
 }
 
 // GetQueryPaginator builds a QueryPaginator using current QueryBuilder instance values.
-func (q *QueryBuilder) GetQueryPaginator(c *dynamodb.Client) *QueryPaginator {
-	return NewQueryPaginator(q.limit, c, NewQueryInput(q))
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by Query API.
+func (q *QueryBuilder) GetQueryPaginator(c *dynamodb.Client) (*QueryPaginator, error) {
+	in, err := NewQueryInput(q)
+	if err != nil {
+		return nil, err
+	}
+	return NewQueryPaginator(q.limit, c, in), nil
 }
 
 // GetQueryReader builds a *QueryReader using current QueryBuilder instance values.
-func (q *QueryBuilder) GetQueryReader(c *dynamodb.Client) *QueryReader {
-	return NewQueryReader(q.limit, c, NewQueryInput(q))
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by Query API.
+func (q *QueryBuilder) GetQueryReader(c *dynamodb.Client) (*QueryReader, error) {
+	in, err := NewQueryInput(q)
+	if err != nil {
+		return nil, err
+	}
+	return NewQueryReader(q.limit, c, in), nil
+}
+
+// GetScanPaginator builds a ScanPaginator using current QueryBuilder instance values.
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by Scan API.
+func (q *QueryBuilder) GetScanPaginator(c *dynamodb.Client) (*ScanPaginator, error) {
+	in, err := NewScanInput(q)
+	if err != nil {
+		return nil, err
+	}
+	return NewScanPaginator(q.limit, c, in), nil
+}
+
+// GetScanReader builds a *ScanReader using current QueryBuilder instance values.
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by Scan API.
+func (q *QueryBuilder) GetScanReader(c *dynamodb.Client) (*ScanReader, error) {
+	in, err := NewScanInput(q)
+	if err != nil {
+		return nil, err
+	}
+	return NewScanReader(q.limit, c, in), nil
+}
+
+// GetParallelScanReader builds a *ParallelScanReader using current QueryBuilder instance values. Scans as many
+// segments as set by DegreeOfParallelism.
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by Scan API.
+func (q *QueryBuilder) GetParallelScanReader(c *dynamodb.Client, policy SegmentErrorPolicy) (*ParallelScanReader,
+	error) {
+	in, err := NewScanInput(q)
+	if err != nil {
+		return nil, err
+	} else if q.parallelDegree > 0 {
+		// segments are set by each worker
+		in.TotalSegments = &q.parallelDegree
+	}
+	return NewParallelScanReader(q.limit, c, in, policy), nil
 }
 
 // ExecGet executes a GetItem API operation.
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by GetItem API.
 func (q *QueryBuilder) ExecGet(ctx context.Context, c *dynamodb.Client) (dynamodb.GetItemOutput, error) {
-	in := NewGetInput(q)
+	in, err := NewGetInput(q)
+	if err != nil {
+		return dynamodb.GetItemOutput{}, err
+	}
 	out, err := c.GetItem(ctx, &in)
 	if err != nil {
 		return dynamodb.GetItemOutput{}, err
@@ -169,9 +264,27 @@ func (q *QueryBuilder) ExecGet(ctx context.Context, c *dynamodb.Client) (dynamod
 	return *out, nil
 }
 
+// ExecGetInto executes a GetItem API operation and decodes the retrieved item into v.
+//
+// Returns ErrItemNotFound if no item was found.
+func (q *QueryBuilder) ExecGetInto(ctx context.Context, c *dynamodb.Client, v Unmarshaler) error {
+	out, err := q.ExecGet(ctx, c)
+	if err != nil {
+		return err
+	} else if len(out.Item) == 0 {
+		return ErrItemNotFound
+	}
+	return v.UnmarshalDynamoDB(out.Item)
+}
+
 // ExecQuery executes a Query API operation.
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by Query API.
 func (q *QueryBuilder) ExecQuery(ctx context.Context, c *dynamodb.Client) (dynamodb.QueryOutput, error) {
-	in := NewQueryInput(q)
+	in, err := NewQueryInput(q)
+	if err != nil {
+		return dynamodb.QueryOutput{}, err
+	}
 	out, err := c.Query(ctx, &in)
 	if err != nil {
 		return dynamodb.QueryOutput{}, err
@@ -179,9 +292,27 @@ func (q *QueryBuilder) ExecQuery(ctx context.Context, c *dynamodb.Client) (dynam
 	return *out, nil
 }
 
+// ExecQueryInto executes a Query API operation and decodes the retrieved items, appending them into v.
+//
+// v MUST be a pointer to a slice of either values or pointers implementing Unmarshaler (e.g. *[]Bill or *[]*Bill).
+//
+// Only a single page is fetched; use GetQueryReader along with NewSchemaReader to iterate over every page.
+func (q *QueryBuilder) ExecQueryInto(ctx context.Context, c *dynamodb.Client, v interface{}) error {
+	out, err := q.ExecQuery(ctx, c)
+	if err != nil {
+		return err
+	}
+	return UnmarshalItems(out.Items, v)
+}
+
 // ExecScan executes a Scan API operation.
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by Scan API.
 func (q *QueryBuilder) ExecScan(ctx context.Context, c *dynamodb.Client) (dynamodb.ScanOutput, error) {
-	in := NewScanInput(q)
+	in, err := NewScanInput(q)
+	if err != nil {
+		return dynamodb.ScanOutput{}, err
+	}
 	out, err := c.Scan(ctx, &in)
 	if err != nil {
 		return dynamodb.ScanOutput{}, err
@@ -1,6 +1,8 @@
 package dynamoql_test
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -32,14 +34,82 @@ func TestNewQueryBuilder(t *testing.T) {
 		Limit(10).
 		DegreeOfParallelism(25).
 		Metrics(types.ReturnConsumedCapacityIndexes)
-	out := dynamoql.NewScanInput(b)
+	// key conditions only accept And operator
+	assert.ErrorIs(t, b.Validate(), dynamoql.ErrInvalidLogicalOperator)
+	out, err := dynamoql.NewScanInput(b.And())
+	require.NoError(t, err)
 	require.NotEmpty(t, out)
 	assert.Equal(t, "sample", *out.TableName)
 	assert.Equal(t, "sample-gsi", *out.IndexName)
 	assert.True(t, *out.ConsistentRead)
-	assert.Equal(t, int32(25), *out.TotalSegments)
+	assert.Nil(t, out.TotalSegments) // only set by GetParallelScanReader, along with Segment
+	assert.Nil(t, out.Segment)
 	assert.Equal(t, int32(10), *out.Limit)
 	assert.EqualValues(t, pageToken, dynamoql.PageToken(out.ExclusiveStartKey))
-	outQuery := dynamoql.NewQueryInput(b)
+	assert.Equal(t, types.SelectSpecificAttributes, out.Select)
+	assert.Equal(t, "#n0", *out.ProjectionExpression)
+	assert.Equal(t, map[string]string{"#n0": "foo"}, out.ExpressionAttributeNames)
+	_, err = dynamoql.NewQueryInput(b)
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidOption) // DegreeOfParallelism is only available for Scan
+	outQuery, err := dynamoql.NewQueryInput(b.DegreeOfParallelism(0))
+	require.NoError(t, err)
 	assert.False(t, *outQuery.ScanIndexForward)
+	assert.Equal(t, "#n0 = :v0", *outQuery.KeyConditionExpression)
+	assert.Equal(t, "#n0", *outQuery.ProjectionExpression)
+}
+
+func TestQueryBuilder_GetParallelScanReader(t *testing.T) {
+	page := `{"Count":1,"Items":[{"PK":{"S":"B#1"},"SK":{"S":"I#1"}}]}`
+	stub := &scanPagesStub{pages: []string{page, page, page}}
+	r, err := dynamoql.Select().From("InvoiceAndBills").DegreeOfParallelism(3).
+		GetParallelScanReader(newScanStubClient(stub), dynamoql.SegmentFailFast)
+	require.NoError(t, err)
+	defer r.Close()
+
+	ctx := context.Background()
+	for r.Next() {
+		if _, err = r.GetItem(ctx); err != nil {
+			break
+		}
+	}
+	assert.ErrorIs(t, err, dynamoql.ErrReaderEOF)
+	assert.Equal(t, 3, r.Count())
+	require.Len(t, stub.requests, 3)
+	for _, req := range stub.requests {
+		assert.Contains(t, req, `"TotalSegments":3`)
+		assert.Contains(t, req, `"Segment":`)
+	}
+}
+
+func TestQueryBuilder_GetScanPaginator_PageToken(t *testing.T) {
+	page := `{"Count":1,"Items":[{"PK":{"S":"B#2"},"SK":{"S":"I#1"}}]}`
+	stub := &scanPagesStub{pages: []string{page, page}}
+	token := dynamoql.PageToken{
+		"PK": &types.AttributeValueMemberS{Value: "B#1"},
+		"SK": &types.AttributeValueMemberS{Value: "I#1"},
+	}
+	p, err := dynamoql.Select().From("InvoiceAndBills").PageToken(token).GetScanPaginator(newScanStubClient(stub))
+	require.NoError(t, err)
+	_, err = p.GetPage(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 1)
+	assertExclusiveStartKey(t, stub.requests[0])
+
+	r, err := dynamoql.Select().From("InvoiceAndBills").PageToken(token).GetScanReader(newScanStubClient(stub))
+	require.NoError(t, err)
+	_, err = r.GetItem(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 2)
+	assertExclusiveStartKey(t, stub.requests[1])
+}
+
+func assertExclusiveStartKey(t *testing.T, req string) {
+	in := struct {
+		ExclusiveStartKey map[string]map[string]string
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(req), &in))
+	assert.Equal(t, map[string]map[string]string{
+		"PK": {"S": "B#1"},
+		"SK": {"S": "I#1"},
+	}, in.ExclusiveStartKey)
 }
@@ -0,0 +1,689 @@
+package dynamoql
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSyntax a DynamoQL query statement is malformed.
+var ErrInvalidSyntax = errors.New("dynamoql: Invalid syntax")
+
+// SyntaxError a DynamoQL query statement parsing failure.
+//
+// Use errors.Is to match ErrInvalidSyntax.
+type SyntaxError struct {
+	// Offset byte offset of the failure within the query statement.
+	Offset int
+	// Line line number of the failure, starting from 1.
+	Line int
+	// Column column number of the failure, starting from 1.
+	Column int
+	// Message description of the failure.
+	Message string
+}
+
+var _ error = SyntaxError{}
+
+func (e SyntaxError) Error() string {
+	return ErrInvalidSyntax.Error() + " at line " + strconv.Itoa(e.Line) + ", column " + strconv.Itoa(e.Column) +
+		": " + e.Message
+}
+
+// Unwrap retrieves the underlying error.
+func (e SyntaxError) Unwrap() error {
+	return ErrInvalidSyntax
+}
+
+func newSyntaxError(query string, offset int, msg string) SyntaxError {
+	if offset > len(query) {
+		offset = len(query)
+	}
+	line := 1 + strings.Count(query[:offset], "\n")
+	column := offset + 1
+	if i := strings.LastIndexByte(query[:offset], '\n'); i >= 0 {
+		column = offset - i
+	}
+	return SyntaxError{
+		Offset:  offset,
+		Line:    line,
+		Column:  column,
+		Message: msg,
+	}
+}
+
+// ParseQuery builds a QueryBuilder from the given DynamoQL query statement, binding each positional parameter (?)
+// to args in order. Parameters and literals are bound as Condition values while bare attribute names used as
+// right-hand operands are bound as FieldRef. Number literals are bound as Decimal, keeping their exact text.
+//
+// The statement syntax is described below, keywords and function names are case-insensitive:
+//
+//	SELECT (* | path [, path ...]) FROM table
+//	[USE INDEX index]
+//	[WHERE key_condition [AND key_condition]]
+//	[FILTER condition]
+//	[ORDER (ASC | DESC)]
+//	[LIMIT number]
+//
+// Key conditions (WHERE) accept comparators (except <>), BETWEEN and begins_with. Filter conditions (FILTER) accept
+// every operator and function, concatenated with AND, OR, NOT and parentheses. Attribute names accept document paths
+// (e.g. address.city, items[2].sku) and might be quoted with backticks (e.g. `created-at`), quoted attribute names
+// are never split into document paths (e.g. `address.city`). String literals are
+// enclosed within single quotes, a quote is escaped by another quote.
+//
+// e.g. SELECT a, b FROM Graph USE INDEX GsiOverload WHERE pk = ? AND begins_with(sk, ?) FILTER amount > ? ORDER DESC
+// LIMIT 50
+//
+// Returns a SyntaxError if the statement is malformed. Use QueryBuilder.Validate to verify the resulting QueryBuilder
+// is accepted by Amazon DynamoDB APIs.
+func ParseQuery(query string, args ...interface{}) (*QueryBuilder, error) {
+	p := queryParser{
+		lexer: queryLexer{
+			query: query,
+		},
+		args: args,
+	}
+	return p.parse()
+}
+
+// MustParseQuery builds a QueryBuilder from the given DynamoQL query statement.
+//
+// Panics if the statement is malformed.
+func MustParseQuery(query string, args ...interface{}) *QueryBuilder {
+	q, err := ParseQuery(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+type queryTokenKind uint8
+
+const (
+	queryTokenEOF queryTokenKind = iota
+	queryTokenIdent
+	queryTokenString
+	queryTokenNumber
+	queryTokenParam
+	queryTokenComparator
+	queryTokenComma
+	queryTokenLeftParen
+	queryTokenRightParen
+	queryTokenStar
+)
+
+// queryToken a lexical unit of a DynamoQL query statement.
+type queryToken struct {
+	kind   queryTokenKind
+	value  string
+	quoted bool
+	offset int
+}
+
+// field retrieves the attribute name of an identifier token, marking unquoted document paths using Path.
+func (t queryToken) field() string {
+	if !t.quoted && strings.ContainsAny(t.value, ".[") {
+		return Path(t.value)
+	}
+	return t.value
+}
+
+// is indicates if the token is the given keyword. Quoted identifiers are never keywords.
+func (t queryToken) is(keyword string) bool {
+	return t.kind == queryTokenIdent && !t.quoted && strings.EqualFold(t.value, keyword)
+}
+
+func (t queryToken) String() string {
+	switch t.kind {
+	case queryTokenEOF:
+		return "end of statement"
+	case queryTokenString:
+		return "'" + t.value + "'"
+	case queryTokenIdent:
+		if t.quoted {
+			return "`" + t.value + "`"
+		}
+	}
+	return t.value
+}
+
+// queryLexer splits a DynamoQL query statement into queryToken(s).
+type queryLexer struct {
+	query string
+	pos   int
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentPart(c byte) bool {
+	// dots and brackets are part of document paths (e.g. items[2].sku)
+	return isIdentStart(c) || isDigit(c) || c == '-' || c == '.' || c == '[' || c == ']'
+}
+
+func (l *queryLexer) next() (queryToken, error) {
+	for l.pos < len(l.query) && strings.IndexByte(" \t\r\n", l.query[l.pos]) >= 0 {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.query) {
+		return queryToken{kind: queryTokenEOF, offset: start}, nil
+	}
+	c := l.query[l.pos]
+	switch {
+	case isIdentStart(c):
+		for l.pos < len(l.query) && isIdentPart(l.query[l.pos]) {
+			l.pos++
+		}
+		return queryToken{kind: queryTokenIdent, value: l.query[start:l.pos], offset: start}, nil
+	case isDigit(c) || ((c == '-' || c == '.') && l.pos+1 < len(l.query) && isDigit(l.query[l.pos+1])):
+		return l.nextNumber(), nil
+	case c == '`':
+		end := strings.IndexByte(l.query[start+1:], '`')
+		if end < 0 {
+			return queryToken{}, newSyntaxError(l.query, start, "unterminated quoted identifier")
+		} else if end == 0 {
+			return queryToken{}, newSyntaxError(l.query, start, "empty quoted identifier")
+		}
+		l.pos = start + end + 2
+		return queryToken{kind: queryTokenIdent, value: l.query[start+1 : start+end+1], quoted: true,
+			offset: start}, nil
+	case c == '\'':
+		return l.nextString()
+	case c == '?':
+		l.pos++
+		return queryToken{kind: queryTokenParam, value: "?", offset: start}, nil
+	case c == ',':
+		l.pos++
+		return queryToken{kind: queryTokenComma, value: ",", offset: start}, nil
+	case c == '(':
+		l.pos++
+		return queryToken{kind: queryTokenLeftParen, value: "(", offset: start}, nil
+	case c == ')':
+		l.pos++
+		return queryToken{kind: queryTokenRightParen, value: ")", offset: start}, nil
+	case c == '*':
+		l.pos++
+		return queryToken{kind: queryTokenStar, value: "*", offset: start}, nil
+	case c == '=':
+		l.pos++
+		return queryToken{kind: queryTokenComparator, value: string(Equals), offset: start}, nil
+	case c == '<' || c == '>':
+		l.pos++
+		if l.pos < len(l.query) && (l.query[l.pos] == '=' || (c == '<' && l.query[l.pos] == '>')) {
+			l.pos++
+		}
+		return queryToken{kind: queryTokenComparator, value: l.query[start:l.pos], offset: start}, nil
+	default:
+		return queryToken{}, newSyntaxError(l.query, start, "unexpected character "+strconv.QuoteRune(rune(c)))
+	}
+}
+
+func (l *queryLexer) nextNumber() queryToken {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.query) {
+		c := l.query[l.pos]
+		if isDigit(c) || c == '.' || c == 'e' || c == 'E' ||
+			((c == '+' || c == '-') && (l.query[l.pos-1] == 'e' || l.query[l.pos-1] == 'E')) {
+			l.pos++
+			continue
+		}
+		break
+	}
+	return queryToken{kind: queryTokenNumber, value: l.query[start:l.pos], offset: start}
+}
+
+func (l *queryLexer) nextString() (queryToken, error) {
+	start := l.pos
+	buf := strings.Builder{}
+	for l.pos++; l.pos < len(l.query); l.pos++ {
+		if l.query[l.pos] != '\'' {
+			buf.WriteByte(l.query[l.pos])
+			continue
+		} else if l.pos+1 < len(l.query) && l.query[l.pos+1] == '\'' {
+			// escaped quote
+			buf.WriteByte('\'')
+			l.pos++
+			continue
+		}
+		l.pos++
+		return queryToken{kind: queryTokenString, value: buf.String(), offset: start}, nil
+	}
+	return queryToken{}, newSyntaxError(l.query, start, "unterminated string literal")
+}
+
+// queryParser a recursive descent parser of DynamoQL query statements.
+type queryParser struct {
+	lexer    queryLexer
+	token    queryToken
+	args     []interface{}
+	argCount int
+}
+
+// filterNode a node of a filter condition tree. Either a Condition (leaf) or a group of nodes.
+type filterNode struct {
+	negate    bool
+	isGroup   bool
+	operator  LogicalOperator
+	nodes     []filterNode
+	condition Condition
+}
+
+// toCondition converts the node into a Condition, holding a ConditionGroup if the node is a group.
+func (n filterNode) toCondition() Condition {
+	if !n.isGroup {
+		c := n.condition
+		c.Negate = n.negate
+		return c
+	}
+	buf := make([]Condition, 0, len(n.nodes))
+	for i := range n.nodes {
+		buf = append(buf, n.nodes[i].toCondition())
+	}
+	c := Group(n.operator, buf...)
+	c.Negate = n.negate
+	return c
+}
+
+func (p *queryParser) errorf(offset int, msg string) error {
+	return newSyntaxError(p.lexer.query, offset, msg)
+}
+
+func (p *queryParser) unexpected(expected string) error {
+	return p.errorf(p.token.offset, "expected "+expected+", got "+p.token.String())
+}
+
+func (p *queryParser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = tok
+	return nil
+}
+
+func (p *queryParser) expectKeyword(keyword string) error {
+	if !p.token.is(keyword) {
+		return p.unexpected(keyword)
+	}
+	return p.advance()
+}
+
+func (p *queryParser) expect(kind queryTokenKind, expected string) error {
+	if p.token.kind != kind {
+		return p.unexpected(expected)
+	}
+	return p.advance()
+}
+
+func (p *queryParser) parse() (*QueryBuilder, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	} else if err = p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	fields, err := p.parseProjection()
+	if err != nil {
+		return nil, err
+	} else if err = p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	table, err := p.parseIdent("table name")
+	if err != nil {
+		return nil, err
+	}
+	q := NewQueryBuilder().Select(fields).From(table)
+	if err = p.parseClauses(q); err != nil {
+		return nil, err
+	} else if p.token.kind != queryTokenEOF {
+		return nil, p.unexpected("end of statement")
+	} else if p.argCount != len(p.args) {
+		return nil, p.errorf(p.token.offset, "statement has "+strconv.Itoa(p.argCount)+" parameters, got "+
+			strconv.Itoa(len(p.args))+" arguments")
+	}
+	return q, nil
+}
+
+func (p *queryParser) parseProjection() ([]string, error) {
+	if p.token.kind == queryTokenStar {
+		return nil, p.advance()
+	}
+	fields := make([]string, 0, 4)
+	for {
+		field, err := p.parseField("attribute name or *")
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		if p.token.kind != queryTokenComma {
+			return fields, nil
+		} else if err = p.advance(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseClauses parses optional clauses, which MUST follow the statement order.
+func (p *queryParser) parseClauses(q *QueryBuilder) (err error) {
+	if p.token.is("USE") {
+		if err = p.advance(); err != nil {
+			return err
+		} else if err = p.expectKeyword("INDEX"); err != nil {
+			return err
+		}
+		index, errIdent := p.parseIdent("index name")
+		if errIdent != nil {
+			return errIdent
+		}
+		q.Index(index)
+	}
+	conditions := make([]Condition, 0, 4)
+	if p.token.is("WHERE") {
+		if err = p.advance(); err != nil {
+			return err
+		} else if conditions, err = p.parseKeyConditions(conditions); err != nil {
+			return err
+		}
+	}
+	if p.token.is("FILTER") {
+		if err = p.advance(); err != nil {
+			return err
+		}
+		root, errFilter := p.parseOr()
+		if errFilter != nil {
+			return errFilter
+		}
+		if root.isGroup && !root.negate {
+			// top-level group operator is used as QueryBuilder operator to keep expressions flat
+			if root.operator == Or {
+				q.Or()
+			}
+			for i := range root.nodes {
+				conditions = append(conditions, root.nodes[i].toCondition())
+			}
+		} else {
+			conditions = append(conditions, root.toCondition())
+		}
+	}
+	if len(conditions) > 0 {
+		q.Where(conditions...)
+	}
+	if p.token.is("ORDER") {
+		if err = p.advance(); err != nil {
+			return err
+		} else if p.token.is(string(Ascend)) {
+			q.OrderBy(Ascend)
+		} else if p.token.is(string(Descend)) {
+			q.OrderBy(Descend)
+		} else {
+			return p.unexpected("ASC or DESC")
+		}
+		if err = p.advance(); err != nil {
+			return err
+		}
+	}
+	if p.token.is("LIMIT") {
+		if err = p.advance(); err != nil {
+			return err
+		} else if p.token.kind != queryTokenNumber {
+			return p.unexpected("limit number")
+		}
+		limit, errLimit := strconv.ParseInt(p.token.value, 10, 32)
+		if errLimit != nil || limit <= 0 {
+			return p.errorf(p.token.offset, "limit must be a positive integer, got "+p.token.value)
+		}
+		q.Limit(int32(limit))
+		return p.advance()
+	}
+	return nil
+}
+
+func (p *queryParser) parseIdent(expected string) (string, error) {
+	if p.token.kind != queryTokenIdent {
+		return "", p.unexpected(expected)
+	}
+	ident := p.token.value
+	return ident, p.advance()
+}
+
+// parseField parses an attribute name or document path (see queryToken.field).
+func (p *queryParser) parseField(expected string) (string, error) {
+	if p.token.kind != queryTokenIdent {
+		return "", p.unexpected(expected)
+	}
+	field := p.token.field()
+	return field, p.advance()
+}
+
+// parseKeyConditions parses key conditions concatenated with AND operators.
+func (p *queryParser) parseKeyConditions(buf []Condition) ([]Condition, error) {
+	for {
+		offset := p.token.offset
+		c, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		c.IsKey = true
+		if err = validateKeyCondition(c); err != nil {
+			var errValidation ValidationError
+			if errors.As(err, &errValidation) {
+				return nil, p.errorf(offset, errValidation.Reason)
+			}
+			return nil, err
+		}
+		buf = append(buf, c)
+		if p.token.is(string(Or)) {
+			return nil, p.errorf(p.token.offset, "key conditions only accept AND operator")
+		} else if !p.token.is(string(And)) {
+			return buf, nil
+		} else if err = p.advance(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseOr parses filter conditions concatenated with OR operators.
+func (p *queryParser) parseOr() (filterNode, error) {
+	return p.parseLogical(Or, p.parseAnd)
+}
+
+// parseAnd parses filter conditions concatenated with AND operators.
+func (p *queryParser) parseAnd() (filterNode, error) {
+	return p.parseLogical(And, p.parseNot)
+}
+
+func (p *queryParser) parseLogical(op LogicalOperator, operand func() (filterNode, error)) (filterNode, error) {
+	n, err := operand()
+	if err != nil {
+		return filterNode{}, err
+	} else if !p.token.is(string(op)) {
+		return n, nil
+	}
+	group := filterNode{
+		isGroup:  true,
+		operator: op,
+		nodes:    []filterNode{n},
+	}
+	for p.token.is(string(op)) {
+		if err = p.advance(); err != nil {
+			return filterNode{}, err
+		}
+		if n, err = operand(); err != nil {
+			return filterNode{}, err
+		}
+		group.nodes = append(group.nodes, n)
+	}
+	return group, nil
+}
+
+// parseNot parses either a negated filter condition, a parenthesised filter condition or a single condition.
+func (p *queryParser) parseNot() (filterNode, error) {
+	if p.token.is(string(not)) {
+		if err := p.advance(); err != nil {
+			return filterNode{}, err
+		}
+		n, err := p.parseNot()
+		if err != nil {
+			return filterNode{}, err
+		}
+		n.negate = !n.negate
+		return n, nil
+	} else if p.token.kind == queryTokenLeftParen {
+		if err := p.advance(); err != nil {
+			return filterNode{}, err
+		}
+		n, err := p.parseOr()
+		if err != nil {
+			return filterNode{}, err
+		}
+		return n, p.expect(queryTokenRightParen, ")")
+	}
+	c, err := p.parseCondition()
+	return filterNode{condition: c}, err
+}
+
+// parseCondition parses a single comparison or function condition.
+func (p *queryParser) parseCondition() (Condition, error) {
+	if p.token.kind != queryTokenIdent {
+		return Condition{}, p.unexpected("condition")
+	}
+	ident := p.token
+	if err := p.advance(); err != nil {
+		return Condition{}, err
+	}
+	if p.token.kind != queryTokenLeftParen || ident.quoted {
+		c := Condition{Field: ident.field()}
+		return c, p.parseComparison(&c, false)
+	}
+	// function
+	switch op := ConditionalOperator(strings.ToLower(ident.value)); op {
+	case Size:
+		c := Condition{Operator: Size}
+		if err := p.parseFuncArgs(&c, 0); err != nil {
+			return Condition{}, err
+		}
+		return c, p.parseComparison(&c, true)
+	case AttributeExists, AttributeNotExists:
+		c := Condition{Operator: op}
+		return c, p.parseFuncArgs(&c, 0)
+	case Contains, BeginsWith, AttributeType:
+		c := Condition{Operator: op}
+		return c, p.parseFuncArgs(&c, 1)
+	default:
+		return Condition{}, p.errorf(ident.offset, "unknown function "+ident.value)
+	}
+}
+
+// parseFuncArgs parses function arguments, an attribute name followed by the given number of operands.
+func (p *queryParser) parseFuncArgs(c *Condition, operands int) (err error) {
+	if err = p.expect(queryTokenLeftParen, "("); err != nil {
+		return err
+	} else if c.Field, err = p.parseField("attribute name"); err != nil {
+		return err
+	}
+	if operands > 0 {
+		if err = p.expect(queryTokenComma, ","); err != nil {
+			return err
+		} else if c.Value, err = p.parseOperand(); err != nil {
+			return err
+		}
+	}
+	return p.expect(queryTokenRightParen, ")")
+}
+
+// parseComparison parses a comparison (comparators, BETWEEN and IN) setting c operator and operands. If isSize,
+// comparison operator is set as c secondary operator.
+func (p *queryParser) parseComparison(c *Condition, isSize bool) (err error) {
+	var op ConditionalOperator
+	switch {
+	case p.token.kind == queryTokenComparator:
+		op = ConditionalOperator(p.token.value)
+		if err = p.advance(); err != nil {
+			return err
+		} else if c.Value, err = p.parseOperand(); err != nil {
+			return err
+		}
+	case p.token.is(string(Between)):
+		op = Between
+		if err = p.advance(); err != nil {
+			return err
+		} else if c.Value, err = p.parseOperand(); err != nil {
+			return err
+		} else if err = p.expectKeyword(string(And)); err != nil {
+			return err
+		}
+		upper, errOperand := p.parseOperand()
+		if errOperand != nil {
+			return errOperand
+		}
+		c.ExtraValues = []interface{}{upper}
+	case p.token.is(string(In)):
+		op = In
+		if err = p.advance(); err != nil {
+			return err
+		} else if err = p.expect(queryTokenLeftParen, "("); err != nil {
+			return err
+		} else if c.Value, err = p.parseOperand(); err != nil {
+			return err
+		}
+		for p.token.kind == queryTokenComma {
+			if err = p.advance(); err != nil {
+				return err
+			}
+			v, errOperand := p.parseOperand()
+			if errOperand != nil {
+				return errOperand
+			}
+			c.ExtraValues = append(c.ExtraValues, v)
+		}
+		if err = p.expect(queryTokenRightParen, ")"); err != nil {
+			return err
+		}
+	default:
+		return p.unexpected("comparison operator")
+	}
+	if isSize {
+		c.SecondaryOperator = op
+	} else {
+		c.Operator = op
+	}
+	return nil
+}
+
+// parseOperand parses a right-hand operand, either a parameter, a literal or an attribute name (FieldRef).
+func (p *queryParser) parseOperand() (interface{}, error) {
+	tok := p.token
+	var v interface{}
+	switch tok.kind {
+	case queryTokenParam:
+		if p.argCount >= len(p.args) {
+			return nil, p.errorf(tok.offset, "missing argument for parameter "+strconv.Itoa(p.argCount+1))
+		}
+		v = p.args[p.argCount]
+		p.argCount++
+	case queryTokenString:
+		v = tok.value
+	case queryTokenNumber:
+		// literal text is kept to avoid losing precision
+		d, err := NewDecimal(tok.value)
+		if err != nil {
+			return nil, p.errorf(tok.offset, "invalid number "+tok.value)
+		}
+		v = d
+	case queryTokenIdent:
+		if tok.is("TRUE") || tok.is("FALSE") {
+			v = tok.is("TRUE")
+		} else {
+			v = FieldRef(tok.field())
+		}
+	default:
+		return nil, p.unexpected("operand")
+	}
+	return v, p.advance()
+}
@@ -0,0 +1,243 @@
+package dynamoql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	q, err := dynamoql.ParseQuery("SELECT a, b FROM Graph USE INDEX GsiOverload "+
+		"WHERE pk = ? AND begins_with(sk, ?) FILTER amount > ? ORDER DESC LIMIT 50", "STUDENT#123", "CLASSROOM#", 100)
+	require.NoError(t, err)
+	require.NoError(t, q.Validate())
+
+	in, err := dynamoql.NewQueryInput(q)
+	require.NoError(t, err)
+	assert.Equal(t, "Graph", *in.TableName)
+	assert.Equal(t, "GsiOverload", *in.IndexName)
+	assert.Equal(t, int32(50), *in.Limit)
+	assert.False(t, *in.ScanIndexForward)
+	assert.Equal(t, "#n0 = :v0 AND begins_with(#n1,:v1)", *in.KeyConditionExpression)
+	assert.Equal(t, "#n2 > :v2", *in.FilterExpression)
+	assert.Equal(t, "#n3,#n4", *in.ProjectionExpression)
+	assert.Equal(t, map[string]string{
+		"#n0": "pk",
+		"#n1": "sk",
+		"#n2": "amount",
+		"#n3": "a",
+		"#n4": "b",
+	}, in.ExpressionAttributeNames)
+	assert.Equal(t, map[string]types.AttributeValue{
+		":v0": &types.AttributeValueMemberS{Value: "STUDENT#123"},
+		":v1": &types.AttributeValueMemberS{Value: "CLASSROOM#"},
+		":v2": &types.AttributeValueMemberN{Value: "100"},
+	}, in.ExpressionAttributeValues)
+}
+
+func TestParseQueryNumbers(t *testing.T) {
+	q, err := dynamoql.ParseQuery("SELECT * FROM t FILTER amount = 12345678901234567890.123456789 OR price IN (1.50, 2)")
+	require.NoError(t, err)
+	in, err := dynamoql.NewScanInput(q)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.AttributeValue{
+		":v0": &types.AttributeValueMemberN{Value: "12345678901234567890.123456789"},
+		":v1": &types.AttributeValueMemberN{Value: "1.50"},
+		":v2": &types.AttributeValueMemberN{Value: "2"},
+	}, in.ExpressionAttributeValues)
+}
+
+func TestParseQueryFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		args   []interface{}
+		exp    string
+		expLen int
+	}{
+		{
+			name:  "Single",
+			query: "SELECT * FROM t FILTER status = 'ACTIVE'",
+			exp:   "#n0 = :v0",
+		},
+		{
+			name:  "Or",
+			query: "select * from t filter status = ? or status = ?",
+			args:  []interface{}{"ACTIVE", "PENDING"},
+			exp:   "#n0 = :v0 OR #n0 = :v1",
+		},
+		{
+			name:  "Precedence",
+			query: "SELECT * FROM t FILTER a = 1 OR b = 2 AND c = 3",
+			exp:   "#n0 = :v0 OR (#n1 = :v1 AND #n2 = :v2)",
+		},
+		{
+			name:  "Parentheses",
+			query: "SELECT * FROM t FILTER (a = 1 OR b = 2) AND NOT contains(tags, 'foo')",
+			exp:   "(#n0 = :v0 OR #n1 = :v1) AND NOT (contains(#n2,:v2))",
+		},
+		{
+			name:  "Negated group",
+			query: "SELECT * FROM t FILTER NOT (a = 1 OR b = 2)",
+			exp:   "NOT (#n0 = :v0 OR #n1 = :v1)",
+		},
+		{
+			name:  "Between and in",
+			query: "SELECT * FROM t FILTER amount BETWEEN 1.5 AND ? AND status IN ('A', 'B', ?)",
+			args:  []interface{}{10, "C"},
+			exp:   "#n0 BETWEEN :v0 AND :v1 AND #n1 IN (:v2,:v3,:v4)",
+		},
+		{
+			name:  "Functions",
+			query: "SELECT * FROM t FILTER attribute_exists(a) AND attribute_not_exists(b) AND attribute_type(c, 'S')",
+			exp:   "attribute_exists(#n0) AND attribute_not_exists(#n1) AND attribute_type(#n2,:v0)",
+		},
+		{
+			name:  "Size",
+			query: "SELECT * FROM t FILTER size(tags) > 2 AND SIZE(items) BETWEEN 1 AND 5 AND size(c) IN (1, 2)",
+			exp:   "size(#n0) > :v0 AND size(#n1) BETWEEN :v1 AND :v2 AND size(#n2) IN (:v3,:v4)",
+		},
+		{
+			name:  "Field reference",
+			query: "SELECT * FROM t FILTER balance < credit_limit AND enabled = true",
+			exp:   "#n0 < #n1 AND #n2 = :v0",
+		},
+		{
+			name:  "Document paths",
+			query: "SELECT * FROM t FILTER address.city = 'Paris' AND items[2].sku <> `created-at`",
+			exp:   "#n0.#n1 = :v0 AND #n2[2].#n3 <> #n4",
+		},
+		{
+			name:  "Quoted dotted names",
+			query: "SELECT * FROM t FILTER `address.city` = 'Paris' AND address.zip = `legacy.zip`",
+			exp:   "#n0 = :v0 AND #n1.#n2 = #n3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := dynamoql.ParseQuery(tt.query, tt.args...)
+			require.NoError(t, err)
+			in, err := dynamoql.NewScanInput(q)
+			require.NoError(t, err)
+			require.NotNil(t, in.FilterExpression)
+			assert.Equal(t, tt.exp, *in.FilterExpression)
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		args      []interface{}
+		expLine   int
+		expColumn int
+	}{
+		{
+			name:      "Empty",
+			query:     "",
+			expLine:   1,
+			expColumn: 1,
+		},
+		{
+			name:      "Missing from",
+			query:     "SELECT a WHERE pk = 1",
+			expLine:   1,
+			expColumn: 10,
+		},
+		{
+			name:      "Trailing comma",
+			query:     "SELECT a, FROM t",
+			expLine:   1,
+			expColumn: 16,
+		},
+		{
+			name:      "Unexpected character",
+			query:     "SELECT * FROM t FILTER a == 1",
+			expLine:   1,
+			expColumn: 27,
+		},
+		{
+			name:      "Invalid number",
+			query:     "SELECT * FROM t FILTER a = 1.2.3",
+			expLine:   1,
+			expColumn: 28,
+		},
+		{
+			name:      "Unterminated string",
+			query:     "SELECT * FROM t\nFILTER a = 'foo",
+			expLine:   2,
+			expColumn: 12,
+		},
+		{
+			name:      "Unknown function",
+			query:     "SELECT * FROM t FILTER ends_with(a, 'foo')",
+			expLine:   1,
+			expColumn: 24,
+		},
+		{
+			name:      "Invalid key condition",
+			query:     "SELECT * FROM t WHERE pk = 1 AND contains(sk, 'foo')",
+			expLine:   1,
+			expColumn: 34,
+		},
+		{
+			name:      "Or key conditions",
+			query:     "SELECT * FROM t WHERE pk = 1 OR sk = 2",
+			expLine:   1,
+			expColumn: 30,
+		},
+		{
+			name:      "Missing argument",
+			query:     "SELECT * FROM t WHERE pk = ? AND sk = ?",
+			args:      []interface{}{"foo"},
+			expLine:   1,
+			expColumn: 39,
+		},
+		{
+			name:      "Too many arguments",
+			query:     "SELECT * FROM t WHERE pk = ?",
+			args:      []interface{}{"foo", "bar"},
+			expLine:   1,
+			expColumn: 29,
+		},
+		{
+			name:      "Invalid ordering",
+			query:     "SELECT * FROM t\n  ORDER BY pk",
+			expLine:   2,
+			expColumn: 9,
+		},
+		{
+			name:      "Invalid limit",
+			query:     "SELECT * FROM t LIMIT 0",
+			expLine:   1,
+			expColumn: 23,
+		},
+		{
+			name:      "Clause order",
+			query:     "SELECT * FROM t LIMIT 10 ORDER DESC",
+			expLine:   1,
+			expColumn: 26,
+		},
+		{
+			name:      "Unbalanced parentheses",
+			query:     "SELECT * FROM t FILTER (a = 1 OR b = 2",
+			expLine:   1,
+			expColumn: 39,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dynamoql.ParseQuery(tt.query, tt.args...)
+			assert.ErrorIs(t, err, dynamoql.ErrInvalidSyntax)
+			var errSyntax dynamoql.SyntaxError
+			require.True(t, errors.As(err, &errSyntax))
+			assert.Equal(t, tt.expLine, errSyntax.Line)
+			assert.Equal(t, tt.expColumn, errSyntax.Column)
+		})
+	}
+}
@@ -103,3 +103,95 @@ func (q *QueryReader) GetItem(ctx context.Context) (map[string]types.AttributeVa
 	q.hasNext = q.buf.PeekAt(q.readPivot) || q.paginator.lastEvalKey != nil
 	return item, nil
 }
+
+// ScanReader iterates for each item stored in an Amazon DynamoDB table using the Scan API.
+//
+// Uses the same pre-fetching strategy as QueryReader, loading chunks of data into an internal buffer before actual
+// item iteration.
+//
+// Some example for using ScanReader:
+//
+//	bills := make([]Bill, 0, 10)
+//	for r.Next() {
+//		item, err := r.GetItem(ctx)
+//		if err != nil {
+//			break
+//		}
+//
+//		bill := Bill{}
+//		if err = bill.UnmarshalDynamoDB(item); err != nil {
+//			break
+//		}
+//
+//		bills = append(bills, bill)
+//		if r.Count() >= 10 {
+//			break
+//		}
+//	}
+type ScanReader struct {
+	paginator *ScanPaginator
+	buf       *ItemBuffer
+	hasNext   bool
+	readPivot int
+	itemCount int
+}
+
+// NewScanReader allocates a ScanReader with required internal components.
+func NewScanReader(chunkSize int32, c *dynamodb.Client, q dynamodb.ScanInput) *ScanReader {
+	return &ScanReader{
+		paginator: NewScanPaginator(chunkSize, c, q),
+		buf:       NewItemBuffer(int(chunkSize)),
+		readPivot: 0,
+		hasNext:   true,
+	}
+}
+
+// Next indicates if there is another item to get.
+func (s *ScanReader) Next() bool {
+	return s.hasNext
+}
+
+// Loads chunks of data into the buffer, replacing already read items.
+//
+// Uses a ScanPaginator as underlying item fetching mechanism. Filtered scan pages might be empty while the table has
+// not been fully scanned yet, so pages are fetched until at least one item is buffered or no pages are left.
+func (s *ScanReader) read(ctx context.Context) error {
+	s.buf.Reset()
+	s.readPivot = 0
+	chunkSize := s.buf.Cap()
+	fetched := 0
+	for s.paginator.Next() {
+		out, err := s.paginator.GetPage(ctx)
+		if err != nil {
+			return err
+		}
+		s.buf.WriteItems(out.Items)
+		fetched += len(out.Items)
+		if fetched > 0 && fetched >= chunkSize {
+			break
+		}
+	}
+	if s.buf.Len() == 0 {
+		return ErrReaderEOF
+	}
+	return nil
+}
+
+// Count returns the count of each item retrieved by a ScanReader instance.
+func (s *ScanReader) Count() int {
+	return s.itemCount
+}
+
+// GetItem retrieves an Item from an Amazon DynamoDB table.
+func (s *ScanReader) GetItem(ctx context.Context) (map[string]types.AttributeValue, error) {
+	if s.buf.Len() == 0 || s.readPivot > s.buf.Len()-1 {
+		if err := s.read(ctx); err != nil {
+			return nil, err
+		}
+	}
+	item := s.buf.ItemAt(s.readPivot)
+	s.readPivot++
+	s.itemCount++
+	s.hasNext = s.buf.PeekAt(s.readPivot) || s.paginator.lastEvalKey != nil
+	return item, nil
+}
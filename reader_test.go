@@ -50,7 +50,7 @@ func (s *queryReaderTestSuite) TestQueryReader_GetItem() {
 		},
 		{
 			name: "Invalid query",
-			query: dynamoql.NewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+			query: mustNewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
 				IsKey:    true,
 				Operator: dynamoql.Equals,
 				Field:    "PK",
@@ -67,7 +67,7 @@ func (s *queryReaderTestSuite) TestQueryReader_GetItem() {
 		},
 		{
 			name: "Valid empty result",
-			query: dynamoql.NewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+			query: mustNewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
 				IsKey:    true,
 				Operator: dynamoql.Equals,
 				Field:    "PK",
@@ -84,7 +84,7 @@ func (s *queryReaderTestSuite) TestQueryReader_GetItem() {
 		},
 		{
 			name: "Valid",
-			query: dynamoql.NewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+			query: mustNewQueryInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
 				IsKey:    true,
 				Operator: dynamoql.Equals,
 				Field:    "PK",
@@ -126,3 +126,72 @@ func (s *queryReaderTestSuite) TestQueryReader_GetItem() {
 		})
 	}
 }
+
+func (s *queryReaderTestSuite) TestScanReader_GetItem() {
+	tests := []struct {
+		name     string
+		query    dynamodb.ScanInput
+		pageSize int32
+		expItems int
+		wantErr  bool
+	}{
+		{
+			name:     "Empty",
+			query:    dynamodb.ScanInput{}, // missing table
+			pageSize: 0,
+			expItems: 0,
+			wantErr:  true,
+		},
+		{
+			name: "Valid empty result",
+			query: mustNewScanInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+				Operator: dynamoql.Equals,
+				Field:    "SK",
+				Value:    "abc",
+			})),
+			pageSize: 100,
+			expItems: 0,
+			wantErr:  true, // Reader has reached end of file (ErrReaderEOF), no items found
+		},
+		{
+			name: "Valid",
+			query: mustNewScanInput(dynamoql.Select().From("InvoiceAndBills").Where(dynamoql.Condition{
+				Operator: dynamoql.BeginsWith,
+				Field:    "PK",
+				Value:    dynamoql.NewCompositeKey("B", ""),
+			}, dynamoql.Condition{
+				Operator: dynamoql.BeginsWith,
+				Field:    "SK",
+				Value:    dynamoql.NewCompositeKey("I", ""),
+			})),
+			pageSize: 100,
+			expItems: 10,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			r := dynamoql.NewScanReader(tt.pageSize, s.client, tt.query)
+
+			ctx := context.Background()
+			bills := make([]Bill, 0, tt.expItems)
+			for r.Next() {
+				item, err := r.GetItem(ctx)
+				require.Equal(t, tt.wantErr, err != nil)
+				if err != nil {
+					break
+				}
+
+				bill := Bill{}
+				err = bill.UnmarshalDynamoDB(item)
+				require.Nil(t, err)
+
+				bills = append(bills, bill)
+				if r.Count() >= tt.expItems {
+					break
+				}
+			}
+			assert.Equal(t, r.Count(), tt.expItems)
+			assert.Len(t, bills, tt.expItems)
+		})
+	}
+}
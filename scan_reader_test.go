@@ -0,0 +1,146 @@
+package dynamoql_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scanPagesStub replies Scan API calls with the given JSON pages, in order, recording request bodies.
+type scanPagesStub struct {
+	mu       sync.Mutex
+	pages    []string
+	calls    int
+	requests []string
+}
+
+func (s *scanPagesStub) Do(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.requests = append(s.requests, string(body))
+	page := s.pages[s.calls]
+	s.calls++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(bytes.NewBufferString(page)),
+	}, nil
+}
+
+func newScanStubClient(stub *scanPagesStub) *dynamodb.Client {
+	return dynamodb.New(dynamodb.Options{
+		Region:           awsLocalRegion,
+		Credentials:      credentials.NewStaticCredentialsProvider("LOCAL", "SECRET", "TOKEN"),
+		EndpointResolver: newDynamoLocalResolver(),
+		HTTPClient:       stub,
+		RetryMaxAttempts: 1,
+	})
+}
+
+func TestScanReader_GetItem_SparsePages(t *testing.T) {
+	const lastKey = `"LastEvaluatedKey":{"PK":{"S":"B#1"},"SK":{"S":"I#1"}}`
+	stub := &scanPagesStub{
+		pages: []string{
+			`{"Count":1,"Items":[{"PK":{"S":"B#1"},"SK":{"S":"I#1"}}],` + lastKey + `}`,
+			`{"Count":0,"Items":[],` + lastKey + `}`,
+			`{"Count":0,"Items":[],` + lastKey + `}`,
+			`{"Count":2,"Items":[{"PK":{"S":"B#2"},"SK":{"S":"I#1"}},{"PK":{"S":"B#3"},"SK":{"S":"I#1"}}],` +
+				lastKey + `}`,
+			`{"Count":0,"Items":[],` + lastKey + `}`,
+			`{"Count":1,"Items":[{"PK":{"S":"B#4"},"SK":{"S":"I#1"}}]}`,
+		},
+	}
+	r := dynamoql.NewScanReader(1, newScanStubClient(stub), dynamodb.ScanInput{
+		TableName: aws.String("InvoiceAndBills"),
+	})
+
+	ctx := context.Background()
+	bills := make([]string, 0, 4)
+	for r.Next() {
+		item, err := r.GetItem(ctx)
+		require.NoError(t, err)
+		bill := Bill{}
+		require.NoError(t, bill.UnmarshalDynamoDB(item))
+		bills = append(bills, bill.InvoiceID)
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4"}, bills)
+	assert.Equal(t, 4, r.Count())
+	assert.Equal(t, len(stub.pages), stub.calls)
+}
+
+func TestScanReader_GetItem_EmptyPages(t *testing.T) {
+	stub := &scanPagesStub{
+		pages: []string{
+			`{"Count":0,"Items":[],"LastEvaluatedKey":{"PK":{"S":"B#1"},"SK":{"S":"I#1"}}}`,
+			`{"Count":0,"Items":[]}`,
+		},
+	}
+	r := dynamoql.NewScanReader(10, newScanStubClient(stub), dynamodb.ScanInput{
+		TableName: aws.String("InvoiceAndBills"),
+	})
+	_, err := r.GetItem(context.Background())
+	assert.ErrorIs(t, err, dynamoql.ErrReaderEOF)
+	assert.Equal(t, 2, stub.calls)
+}
+
+func TestParallelScanReader_GetItem_CallContext(t *testing.T) {
+	const lastKey = `"LastEvaluatedKey":{"PK":{"S":"B#1"},"SK":{"S":"I#1"}}`
+	stub := &scanPagesStub{
+		pages: []string{
+			`{"Count":1,"Items":[{"PK":{"S":"B#1"},"SK":{"S":"I#1"}}],` + lastKey + `}`,
+			`{"Count":1,"Items":[{"PK":{"S":"B#2"},"SK":{"S":"I#1"}}],` + lastKey + `}`,
+			`{"Count":1,"Items":[{"PK":{"S":"B#3"},"SK":{"S":"I#1"}}]}`,
+		},
+	}
+	r := dynamoql.NewParallelScanReader(1, newScanStubClient(stub), dynamodb.ScanInput{
+		TableName: aws.String("InvoiceAndBills"),
+	}, dynamoql.SegmentFailFast)
+	defer r.Close()
+
+	// workers must outlive the context given to the first call
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := r.GetItem(ctx)
+	require.NoError(t, err)
+	cancel()
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	for r.Next() {
+		if _, err = r.GetItem(ctx); err != nil {
+			break
+		}
+	}
+	assert.ErrorIs(t, err, dynamoql.ErrReaderEOF)
+	assert.Equal(t, 3, r.Count())
+	assert.Empty(t, r.Errors())
+}
+
+func TestParallelScanReader_GetItem_Closed(t *testing.T) {
+	stub := &scanPagesStub{}
+	r := dynamoql.NewParallelScanReader(10, newScanStubClient(stub), dynamodb.ScanInput{
+		TableName: aws.String("InvoiceAndBills"),
+	}, dynamoql.SegmentFailFast)
+	r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	_, err := r.GetItem(ctx)
+	assert.ErrorIs(t, err, dynamoql.ErrReaderEOF)
+	assert.False(t, r.Next())
+	assert.Zero(t, stub.calls)
+}
@@ -0,0 +1,133 @@
+package dynamoql
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// UnmarshalerPtr a pointer to T implementing Unmarshaler. Used by typed components to allocate and decode T values.
+type UnmarshalerPtr[T any] interface {
+	*T
+	Unmarshaler
+}
+
+// ItemReader iterates for each item stored in an Amazon DynamoDB table.
+//
+// Implemented by QueryReader, ScanReader and ParallelScanReader.
+type ItemReader interface {
+	// Next indicates if there is another item to get.
+	Next() bool
+	// GetItem retrieves an Item from an Amazon DynamoDB table.
+	GetItem(ctx context.Context) (map[string]types.AttributeValue, error)
+	// Count returns the count of each item retrieved.
+	Count() int
+}
+
+var (
+	_ ItemReader = &QueryReader{}
+	_ ItemReader = &ScanReader{}
+	_ ItemReader = &ParallelScanReader{}
+)
+
+// ItemPaginator fetches pages of items stored in an Amazon DynamoDB table.
+//
+// Implemented by QueryPaginator and ScanPaginator.
+type ItemPaginator interface {
+	// Next indicates if there is another page to get.
+	Next() bool
+	// NextPageToken retrieves the token of the next page.
+	NextPageToken() PageToken
+	// GetItems retrieves the items of the next page.
+	GetItems(ctx context.Context) ([]map[string]types.AttributeValue, error)
+}
+
+var (
+	_ ItemPaginator = &QueryPaginator{}
+	_ ItemPaginator = &ScanPaginator{}
+)
+
+// SchemaReader iterates for each item stored in an Amazon DynamoDB table, decoding items into T values.
+//
+// Wraps an ItemReader, hence it shares the same pre-fetching strategy. Some example for using SchemaReader:
+//
+//	qr, err := q.GetQueryReader(c)
+//	if err != nil {
+//		return err
+//	}
+//	r := dynamoql.NewSchemaReader[Bill](qr)
+//	for r.Next() {
+//		bill, err := r.GetItem(ctx)
+//		if err != nil {
+//			break
+//		}
+//		// ...
+//	}
+type SchemaReader[T any, PT UnmarshalerPtr[T]] struct {
+	reader ItemReader
+}
+
+// NewSchemaReader allocates a SchemaReader using the given ItemReader.
+func NewSchemaReader[T any, PT UnmarshalerPtr[T]](r ItemReader) *SchemaReader[T, PT] {
+	return &SchemaReader[T, PT]{
+		reader: r,
+	}
+}
+
+// Next indicates if there is another item to get.
+func (r *SchemaReader[T, PT]) Next() bool {
+	return r.reader.Next()
+}
+
+// Count returns the count of each item retrieved by a SchemaReader instance.
+func (r *SchemaReader[T, PT]) Count() int {
+	return r.reader.Count()
+}
+
+// GetItem retrieves an Item from an Amazon DynamoDB table and decodes it.
+func (r *SchemaReader[T, PT]) GetItem(ctx context.Context) (T, error) {
+	var v T
+	item, err := r.reader.GetItem(ctx)
+	if err != nil {
+		return v, err
+	}
+	err = PT(&v).UnmarshalDynamoDB(item)
+	return v, err
+}
+
+// SchemaPaginator fetches pages of items stored in an Amazon DynamoDB table, decoding items into T values.
+type SchemaPaginator[T any, PT UnmarshalerPtr[T]] struct {
+	paginator ItemPaginator
+}
+
+// NewSchemaPaginator allocates a SchemaPaginator using the given ItemPaginator.
+func NewSchemaPaginator[T any, PT UnmarshalerPtr[T]](p ItemPaginator) *SchemaPaginator[T, PT] {
+	return &SchemaPaginator[T, PT]{
+		paginator: p,
+	}
+}
+
+// Next indicates if there is another page to get.
+func (p *SchemaPaginator[T, PT]) Next() bool {
+	return p.paginator.Next()
+}
+
+// NextPageToken retrieves the token of the next page.
+func (p *SchemaPaginator[T, PT]) NextPageToken() PageToken {
+	return p.paginator.NextPageToken()
+}
+
+// GetPage retrieves the next page and decodes its items.
+func (p *SchemaPaginator[T, PT]) GetPage(ctx context.Context) ([]T, error) {
+	items, err := p.paginator.GetItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]T, len(items))
+	for i := range items {
+		if err = PT(&buf[i]).UnmarshalDynamoDB(items[i]); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
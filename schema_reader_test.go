@@ -0,0 +1,118 @@
+package dynamoql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type itemReaderStub struct {
+	items []map[string]types.AttributeValue
+	err   error
+	pivot int
+}
+
+var _ dynamoql.ItemReader = &itemReaderStub{}
+
+func (r *itemReaderStub) Next() bool {
+	return r.pivot < len(r.items) || r.err != nil
+}
+
+func (r *itemReaderStub) GetItem(_ context.Context) (map[string]types.AttributeValue, error) {
+	if r.pivot >= len(r.items) {
+		if r.err != nil {
+			return nil, r.err
+		}
+		return nil, dynamoql.ErrReaderEOF
+	}
+	r.pivot++
+	return r.items[r.pivot-1], nil
+}
+
+func (r *itemReaderStub) Count() int {
+	return r.pivot
+}
+
+type itemPaginatorStub struct {
+	pages [][]map[string]types.AttributeValue
+	pivot int
+}
+
+var _ dynamoql.ItemPaginator = &itemPaginatorStub{}
+
+func (p *itemPaginatorStub) Next() bool {
+	return p.pivot < len(p.pages)
+}
+
+func (p *itemPaginatorStub) NextPageToken() dynamoql.PageToken {
+	if !p.Next() {
+		return nil
+	}
+	return dynamoql.PageToken{}
+}
+
+func (p *itemPaginatorStub) GetItems(_ context.Context) ([]map[string]types.AttributeValue, error) {
+	p.pivot++
+	return p.pages[p.pivot-1], nil
+}
+
+func TestSchemaReader_GetItem(t *testing.T) {
+	errStub := errors.New("stub error")
+	r := dynamoql.NewSchemaReader[Bill](&itemReaderStub{
+		items: []map[string]types.AttributeValue{
+			Bill{InvoiceID: "1", BillID: "2"}.GetKeys(),
+			Bill{InvoiceID: "1", BillID: "3"}.GetKeys(),
+		},
+		err: errStub,
+	})
+	ctx := context.Background()
+	bills := make([]Bill, 0, 2)
+	var err error
+	for r.Next() {
+		var bill Bill
+		bill, err = r.GetItem(ctx)
+		if err != nil {
+			break
+		}
+		bills = append(bills, bill)
+	}
+	assert.ErrorIs(t, err, errStub)
+	assert.Equal(t, 2, r.Count())
+	assert.Equal(t, []Bill{
+		{InvoiceID: "1", BillID: "2"},
+		{InvoiceID: "1", BillID: "3"},
+	}, bills)
+}
+
+func TestSchemaPaginator_GetPage(t *testing.T) {
+	p := dynamoql.NewSchemaPaginator[Bill](&itemPaginatorStub{
+		pages: [][]map[string]types.AttributeValue{
+			{
+				Bill{InvoiceID: "1", BillID: "2"}.GetKeys(),
+				Bill{InvoiceID: "1", BillID: "3"}.GetKeys(),
+			},
+			{
+				Bill{InvoiceID: "1", BillID: "4"}.GetKeys(),
+			},
+		},
+	})
+	ctx := context.Background()
+	bills := make([]Bill, 0, 3)
+	for p.Next() {
+		assert.NotNil(t, p.NextPageToken())
+		page, err := p.GetPage(ctx)
+		require.NoError(t, err)
+		bills = append(bills, page...)
+	}
+	assert.Nil(t, p.NextPageToken())
+	assert.Equal(t, []Bill{
+		{InvoiceID: "1", BillID: "2"},
+		{InvoiceID: "1", BillID: "3"},
+		{InvoiceID: "1", BillID: "4"},
+	}, bills)
+}
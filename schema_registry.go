@@ -0,0 +1,175 @@
+package dynamoql
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var (
+	// ErrUnknownSchema the schema name of an item is not registered in a SchemaRegistry.
+	ErrUnknownSchema = errors.New("dynamoql: Unknown schema")
+	// ErrSchemaAlreadyRegistered a schema with the same name was already registered in a SchemaRegistry.
+	ErrSchemaAlreadyRegistered = errors.New("dynamoql: Schema already registered")
+)
+
+// UnknownSchemaError an item with a schema name not registered in a SchemaRegistry. Matches ErrUnknownSchema.
+type UnknownSchemaError struct {
+	Schema string
+}
+
+var _ error = UnknownSchemaError{}
+
+func (e UnknownSchemaError) Error() string {
+	return ErrUnknownSchema.Error() + " " + strconv.Quote(e.Schema)
+}
+
+// Is indicates if target is ErrUnknownSchema.
+func (e UnknownSchemaError) Is(target error) bool {
+	return target == ErrUnknownSchema
+}
+
+// UnknownSchemaPolicy Used to set the behaviour of a SchemaRegistry when an item schema is not registered.
+type UnknownSchemaPolicy string
+
+const (
+	// UnknownSchemaSkip ignores items with unknown schemas.
+	UnknownSchemaSkip UnknownSchemaPolicy = "SKIP"
+	// UnknownSchemaFail stops decoding and returns an UnknownSchemaError.
+	UnknownSchemaFail UnknownSchemaPolicy = "FAIL"
+	// UnknownSchemaRaw decodes items with unknown schemas into RawItem.
+	UnknownSchemaRaw UnknownSchemaPolicy = "RAW"
+)
+
+// RawItem an Amazon DynamoDB item with an unknown schema, decoded as is.
+type RawItem map[string]types.AttributeValue
+
+var _ Schema = &RawItem{}
+
+// MarshalDynamoDB retrieves the item as is.
+func (i RawItem) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
+	return i, nil
+}
+
+// UnmarshalDynamoDB stores the given item as is.
+func (i *RawItem) UnmarshalDynamoDB(m map[string]types.AttributeValue) error {
+	*i = m
+	return nil
+}
+
+// SchemaFactory allocates an empty NodeSchema ready to decode an item. MUST return a pointer as decoding requires
+// modifying the schema (e.g. &Student{}).
+type SchemaFactory func() NodeSchema
+
+// SchemaRegistry decodes items of multiple schemas stored in a single table (single-table design) into their
+// concrete types.
+//
+// Schemas are identified by the attribute named after DefaultSchemaField (at the time the registry was allocated),
+// hence every registered schema MUST write its name (GetName) into such attribute when marshaling.
+//
+// A SchemaRegistry is safe for concurrent use.
+//
+// e.g.
+//
+//	r := dynamoql.NewSchemaRegistry(dynamoql.UnknownSchemaSkip)
+//	r.MustRegister(func() dynamoql.NodeSchema { return &Student{} })
+//	schemas, err := r.DecodeItems(out.Items)
+//	for _, schema := range schemas {
+//		switch s := schema.(type) {
+//		case *Student:
+//			// ...
+//		}
+//	}
+type SchemaRegistry struct {
+	field     string
+	policy    UnknownSchemaPolicy
+	mu        sync.RWMutex
+	factories map[string]SchemaFactory
+}
+
+// NewSchemaRegistry allocates a SchemaRegistry with the given unknown schema policy. UnknownSchemaFail is used if
+// policy is empty.
+func NewSchemaRegistry(policy UnknownSchemaPolicy) *SchemaRegistry {
+	if policy == "" {
+		policy = UnknownSchemaFail
+	}
+	return &SchemaRegistry{
+		field:     DefaultSchemaField,
+		policy:    policy,
+		factories: make(map[string]SchemaFactory),
+	}
+}
+
+// Register stores the given factory under the name of the schema it allocates (GetName).
+//
+// Returns ErrSchemaAlreadyRegistered if another factory was registered under the same name.
+func (r *SchemaRegistry) Register(f SchemaFactory) error {
+	name := f().GetName()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[name]; ok {
+		return ErrSchemaAlreadyRegistered
+	}
+	r.factories[name] = f
+	return nil
+}
+
+// MustRegister stores the given factory under the name of the schema it allocates (GetName).
+//
+// Panics if another factory was registered under the same name.
+func (r *SchemaRegistry) MustRegister(f SchemaFactory) *SchemaRegistry {
+	if err := r.Register(f); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Decode allocates and decodes the schema of the given item.
+//
+// Returns nil if item is nil or if its schema is unknown and UnknownSchemaSkip policy is set.
+// Returns an UnknownSchemaError if schema is unknown and UnknownSchemaFail policy is set.
+func (r *SchemaRegistry) Decode(item map[string]types.AttributeValue) (Schema, error) {
+	if item == nil {
+		return nil, nil
+	}
+	name, _ := ParseString(item[r.field])
+	r.mu.RLock()
+	f, ok := r.factories[name]
+	r.mu.RUnlock()
+	if !ok {
+		switch r.policy {
+		case UnknownSchemaSkip:
+			return nil, nil
+		case UnknownSchemaRaw:
+			raw := RawItem(item)
+			return &raw, nil
+		default:
+			return nil, UnknownSchemaError{Schema: name}
+		}
+	}
+	schema := f()
+	if err := schema.UnmarshalDynamoDB(item); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// DecodeItems allocates and decodes the schema of each item, keeping items order. Nil items and items skipped by
+// UnknownSchemaSkip policy are omitted.
+//
+// Returns an UnknownSchemaError if a schema is unknown and UnknownSchemaFail policy is set.
+func (r *SchemaRegistry) DecodeItems(items []map[string]types.AttributeValue) ([]Schema, error) {
+	buf := make([]Schema, 0, len(items))
+	for i := range items {
+		schema, err := r.Decode(items[i])
+		if err != nil {
+			return nil, err
+		} else if schema == nil {
+			continue
+		}
+		buf = append(buf, schema)
+	}
+	return buf, nil
+}
@@ -0,0 +1,129 @@
+package dynamoql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Invoice struct {
+	InvoiceID string
+	Status    string
+}
+
+var _ dynamoql.NodeSchema = &Invoice{}
+
+func (i Invoice) GetName() string {
+	return "Invoice"
+}
+
+func (i Invoice) GetKeys() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": dynamoql.FormatAttribute(dynamoql.NewCompositeKey("I", i.InvoiceID)),
+		"SK": dynamoql.FormatAttribute("root"),
+	}
+}
+
+func (i Invoice) MarshalDynamoDB() (map[string]types.AttributeValue, error) {
+	return map[string]types.AttributeValue{
+		"PK":                        dynamoql.FormatAttribute(dynamoql.NewCompositeKey("I", i.InvoiceID)),
+		"SK":                        dynamoql.FormatAttribute("root"),
+		"InvoiceStatus":             dynamoql.FormatAttribute(i.Status),
+		dynamoql.DefaultSchemaField: dynamoql.FormatAttribute(i.GetName()),
+	}, nil
+}
+
+func (i *Invoice) UnmarshalDynamoDB(m map[string]types.AttributeValue) error {
+	if m["InvoiceStatus"] == nil {
+		return errors.New("missing invoice status")
+	}
+	i.InvoiceID = dynamoql.ParseCompositeKey(dynamoql.MustParseString(m["PK"]))
+	i.Status = dynamoql.MustParseString(m["InvoiceStatus"])
+	return nil
+}
+
+func TestSchemaRegistry_DecodeItems(t *testing.T) {
+	invoice, _ := Invoice{InvoiceID: "1191", Status: "PAID"}.MarshalDynamoDB()
+	bill, _ := Bill{InvoiceID: "1191", BillID: "1", Amount: "100"}.MarshalDynamoDB()
+	bill[dynamoql.DefaultSchemaField] = dynamoql.FormatAttribute("Bill")
+	customer := map[string]types.AttributeValue{
+		"PK":                        dynamoql.FormatAttribute("C#1"),
+		dynamoql.DefaultSchemaField: dynamoql.FormatAttribute("Customer"),
+	}
+	items := []map[string]types.AttributeValue{invoice, nil, customer, bill}
+
+	tests := []struct {
+		name   string
+		policy dynamoql.UnknownSchemaPolicy
+		exp    []dynamoql.Schema
+		expErr error
+	}{
+		{
+			name:   "Fail",
+			policy: dynamoql.UnknownSchemaFail,
+			expErr: dynamoql.ErrUnknownSchema,
+		},
+		{
+			name:   "Default policy",
+			expErr: dynamoql.ErrUnknownSchema,
+		},
+		{
+			name:   "Skip",
+			policy: dynamoql.UnknownSchemaSkip,
+			exp: []dynamoql.Schema{
+				&Invoice{InvoiceID: "1191", Status: "PAID"},
+				&Bill{InvoiceID: "1191", BillID: "1", Amount: "100"},
+			},
+		},
+		{
+			name:   "Raw",
+			policy: dynamoql.UnknownSchemaRaw,
+			exp: []dynamoql.Schema{
+				&Invoice{InvoiceID: "1191", Status: "PAID"},
+				(*dynamoql.RawItem)(&customer),
+				&Bill{InvoiceID: "1191", BillID: "1", Amount: "100"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := dynamoql.NewSchemaRegistry(tt.policy).
+				MustRegister(func() dynamoql.NodeSchema { return &Invoice{} }).
+				MustRegister(func() dynamoql.NodeSchema { return &Bill{} })
+			schemas, err := r.DecodeItems(items)
+			assert.ErrorIs(t, err, tt.expErr)
+			assert.Equal(t, tt.exp, schemas)
+		})
+	}
+}
+
+func TestSchemaRegistry_Decode(t *testing.T) {
+	r := dynamoql.NewSchemaRegistry(dynamoql.UnknownSchemaFail)
+	require.NoError(t, r.Register(func() dynamoql.NodeSchema { return &Invoice{} }))
+	assert.ErrorIs(t, r.Register(func() dynamoql.NodeSchema { return &Invoice{} }),
+		dynamoql.ErrSchemaAlreadyRegistered)
+	assert.Panics(t, func() {
+		r.MustRegister(func() dynamoql.NodeSchema { return &Invoice{} })
+	})
+
+	schema, err := r.Decode(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, schema)
+
+	_, err = r.Decode(map[string]types.AttributeValue{
+		dynamoql.DefaultSchemaField: dynamoql.FormatAttribute("Invoice"),
+	})
+	assert.EqualError(t, err, "missing invoice status")
+
+	_, err = r.Decode(map[string]types.AttributeValue{
+		dynamoql.DefaultSchemaField: dynamoql.FormatAttribute("Customer"),
+	})
+	var errSchema dynamoql.UnknownSchemaError
+	require.True(t, errors.As(err, &errSchema))
+	assert.Equal(t, "Customer", errSchema.Schema)
+	assert.EqualError(t, err, `dynamoql: Unknown schema "Customer"`)
+}
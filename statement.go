@@ -0,0 +1,421 @@
+package dynamoql
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Statement a PartiQL statement along its positional parameters, ready to be used by ExecuteStatement API.
+//
+// See ref: https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/ql-reference.html
+type Statement struct {
+	// Query PartiQL statement using a question mark (?) for each parameter.
+	Query string
+	// Parameters values bound to each question mark of Query, in order.
+	Parameters []types.AttributeValue
+}
+
+var _ fmt.Stringer = Statement{}
+
+// String renders the statement with its parameters inlined. Useful for logging; DO NOT execute the output as
+// parameters are not escaped for every attribute type (e.g. binary values).
+func (s Statement) String() string {
+	buf := strings.Builder{}
+	buf.Grow(len(s.Query))
+	param := 0
+	quoted := false
+	for i := 0; i < len(s.Query); i++ {
+		switch c := s.Query[i]; {
+		case c == '"':
+			quoted = !quoted
+			buf.WriteByte(c)
+		case c == '?' && !quoted && param < len(s.Parameters):
+			writeStatementParameter(&buf, s.Parameters[param])
+			param++
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+func writeStatementParameter(buf *strings.Builder, v types.AttributeValue) {
+	switch val := v.(type) {
+	case *types.AttributeValueMemberS:
+		writeStatementString(buf, val.Value)
+	case *types.AttributeValueMemberN:
+		buf.WriteString(val.Value)
+	case *types.AttributeValueMemberBOOL:
+		buf.WriteString(strconv.FormatBool(val.Value))
+	case *types.AttributeValueMemberNULL:
+		buf.WriteString("NULL")
+	case *types.AttributeValueMemberB:
+		writeStatementString(buf, base64.StdEncoding.EncodeToString(val.Value))
+	case *types.AttributeValueMemberSS:
+		buf.WriteString("<<")
+		for i := range val.Value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeStatementString(buf, val.Value[i])
+		}
+		buf.WriteString(">>")
+	case *types.AttributeValueMemberNS:
+		buf.WriteString("<<")
+		buf.WriteString(strings.Join(val.Value, ", "))
+		buf.WriteString(">>")
+	case *types.AttributeValueMemberBS:
+		buf.WriteString("<<")
+		for i := range val.Value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeStatementString(buf, base64.StdEncoding.EncodeToString(val.Value[i]))
+		}
+		buf.WriteString(">>")
+	case *types.AttributeValueMemberL:
+		buf.WriteByte('[')
+		for i := range val.Value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeStatementParameter(buf, val.Value[i])
+		}
+		buf.WriteByte(']')
+	case *types.AttributeValueMemberM:
+		keys := make([]string, 0, len(val.Value))
+		for k := range val.Value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i := range keys {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeStatementString(buf, keys[i])
+			buf.WriteString(": ")
+			writeStatementParameter(buf, val.Value[keys[i]])
+		}
+		buf.WriteByte('}')
+	default:
+		buf.WriteByte('?')
+	}
+}
+
+func writeStatementString(buf *strings.Builder, v string) {
+	buf.WriteByte('\'')
+	buf.WriteString(strings.ReplaceAll(v, "'", "''"))
+	buf.WriteByte('\'')
+}
+
+// statementWriter crafts PartiQL statements. Attribute names are always quoted while attribute values are written
+// as positional parameters.
+type statementWriter struct {
+	buf    strings.Builder
+	params []types.AttributeValue
+	err    error
+}
+
+// writeIdent writes a quoted identifier (e.g. "foo").
+func (w *statementWriter) writeIdent(v string) {
+	w.buf.WriteByte('"')
+	w.buf.WriteString(strings.ReplaceAll(v, `"`, `""`))
+	w.buf.WriteByte('"')
+}
+
+// writePath writes a field, quoting each attribute name element of document paths marked by Path (e.g.
+// "address"."city" or "items"[2]).
+func (w *statementWriter) writePath(field string) {
+	elems := fieldElements(field)
+	for i := range elems {
+		if elems[i].isIndex {
+			w.buf.WriteByte(documentPathIndexStart)
+			w.buf.WriteString(strconv.Itoa(elems[i].index))
+			w.buf.WriteByte(documentPathIndexEnd)
+			continue
+		} else if i > 0 {
+			w.buf.WriteByte(documentPathSeparator)
+		}
+		w.writeIdent(elems[i].name)
+	}
+}
+
+// writeTable writes the table name, followed by the index name if not nil (e.g. "Graph"."GsiOverload").
+func (w *statementWriter) writeTable(table string, index *string) {
+	w.writeIdent(table)
+	if index != nil {
+		w.buf.WriteByte('.')
+		w.writeIdent(*index)
+	}
+}
+
+// writeOperand writes a right-hand operand. FieldRef operands are written as fields (see writePath) while any other
+// value is written as a parameter.
+func (w *statementWriter) writeOperand(field string, v interface{}) {
+	if ref, ok := v.(FieldRef); ok {
+		w.writePath(string(ref))
+		return
+	}
+	w.writeParameter(field, v)
+}
+
+// writeParameter writes a parameter placeholder, registering v formatted as an Amazon DynamoDB attribute.
+func (w *statementWriter) writeParameter(field string, v interface{}) {
+	attr, err := MarshalAttribute(v)
+	if err != nil && w.err == nil {
+		w.err = newValidationError(ErrInvalidCondition, field, fmt.Sprintf("unsupported value type %T", v))
+	}
+	w.params = append(w.params, attr)
+	w.buf.WriteByte('?')
+}
+
+// writeConditions writes the given Condition(s) concatenated by operator.
+func (w *statementWriter) writeConditions(operator LogicalOperator, negate bool, c []Condition) {
+	operator = newLogicalOperator(operator)
+	if negate {
+		w.buf.WriteString(string(not) + " (")
+	}
+	written := 0
+	for i := range c {
+		if c[i].Group == nil && c[i].Field == "" {
+			continue
+		} else if c[i].Group != nil && len(c[i].Group.Conditions) == 0 {
+			continue
+		}
+		if written > 0 {
+			w.buf.WriteByte(' ')
+			w.buf.WriteString(string(operator))
+			w.buf.WriteByte(' ')
+		}
+		w.writeCondition(c[i])
+		written++
+	}
+	if negate {
+		w.buf.WriteByte(')')
+	}
+}
+
+// writeCondition writes a single Condition. Groups are enclosed within parentheses.
+func (w *statementWriter) writeCondition(c Condition) {
+	if c.Group != nil && len(c.Group.Conditions) == 1 && !c.Negate {
+		// parentheses are redundant for single-condition groups
+		w.writeCondition(c.Group.Conditions[0])
+		return
+	} else if c.Negate && !c.IsKey {
+		w.buf.WriteString(string(not) + " ")
+	}
+	if c.Group != nil {
+		w.buf.WriteByte('(')
+		w.writeConditions(c.Group.Operator, false, c.Group.Conditions)
+		w.buf.WriteByte(')')
+		return
+	} else if c.Negate && !c.IsKey {
+		w.buf.WriteByte('(')
+		defer w.buf.WriteByte(')')
+	}
+	switch c.Operator {
+	case AttributeExists:
+		w.writePath(c.Field)
+		w.buf.WriteString(" IS NOT MISSING")
+	case AttributeNotExists:
+		w.writePath(c.Field)
+		w.buf.WriteString(" IS MISSING")
+	case Contains, BeginsWith, AttributeType:
+		w.buf.WriteString(string(c.Operator))
+		w.buf.WriteByte('(')
+		w.writePath(c.Field)
+		w.buf.WriteString(", ")
+		w.writeOperand(c.Field, c.Value)
+		w.buf.WriteByte(')')
+	case Size:
+		w.buf.WriteString(string(Size) + "(")
+		w.writePath(c.Field)
+		w.buf.WriteByte(')')
+		w.writeComparison(c.SecondaryOperator, c)
+	default:
+		w.writePath(c.Field)
+		w.writeComparison(c.Operator, c)
+	}
+}
+
+// writeComparison writes a comparison without its left-hand operand (e.g. BETWEEN ? AND ?).
+func (w *statementWriter) writeComparison(op ConditionalOperator, c Condition) {
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(string(op))
+	w.buf.WriteByte(' ')
+	switch op {
+	case Between:
+		w.writeOperand(c.Field, c.Value)
+		w.buf.WriteString(" " + string(And) + " ")
+		if len(c.ExtraValues) > 0 {
+			w.writeOperand(c.Field, c.ExtraValues[0])
+		}
+	case In:
+		w.buf.WriteByte('[')
+		w.writeOperand(c.Field, c.Value)
+		for i := range c.ExtraValues {
+			w.buf.WriteString(", ")
+			w.writeOperand(c.Field, c.ExtraValues[i])
+		}
+		w.buf.WriteByte(']')
+	default:
+		w.writeOperand(c.Field, c.Value)
+	}
+}
+
+// writeWhere writes a WHERE clause using the QueryBuilder conditions. Key conditions are concatenated with an And
+// operator to the original filter conditions.
+func (w *statementWriter) writeWhere(q *QueryBuilder) {
+	op, negate, conditions := newScanConditions(q.operator, q.negate, q.conditions)
+	if len(conditions) == 0 {
+		return
+	}
+	w.buf.WriteString(" WHERE ")
+	w.writeConditions(op, negate, conditions)
+}
+
+// writeOrderBy writes an ORDER BY clause if QueryBuilder ordering is descendant. Results are sorted by the sort key
+// if present, otherwise by the partition key.
+func (w *statementWriter) writeOrderBy(q *QueryBuilder) {
+	if q.ordering != Descend {
+		return
+	}
+	keys := keyConditions(q.conditions)
+	if len(keys) == 0 {
+		return
+	}
+	// sort key condition is either the non-Equals key condition or the one set after the partition key condition
+	key := keys[len(keys)-1]
+	for i := range keys {
+		if keys[i].Operator != Equals {
+			key = keys[i]
+			break
+		}
+	}
+	w.buf.WriteString(" ORDER BY ")
+	w.writePath(key.Field)
+	w.buf.WriteString(" " + string(Descend))
+}
+
+func (w *statementWriter) statement() (Statement, error) {
+	if w.err != nil {
+		return Statement{}, w.err
+	}
+	return Statement{
+		Query:      w.buf.String(),
+		Parameters: w.params,
+	}, nil
+}
+
+// NewSelectStatement builds a PartiQL SELECT Statement using current QueryBuilder instance values.
+//
+// e.g. SELECT "a", "b" FROM "Graph"."GsiOverload" WHERE "pk" = ? AND begins_with("sk", ?) AND "amount" > ?
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by ExecuteStatement API.
+func NewSelectStatement(q *QueryBuilder) (Statement, error) {
+	if err := q.Validate(); err != nil {
+		return Statement{}, err
+	} else if q.parallelDegree > 0 {
+		return Statement{}, newValidationError(ErrInvalidOption, "",
+			"degree of parallelism is only available for Scan operations")
+	}
+	w := statementWriter{}
+	w.buf.WriteString("SELECT ")
+	projected := 0
+	for i := range q.projectedFields {
+		if q.projectedFields[i] == "" {
+			continue
+		} else if projected > 0 {
+			w.buf.WriteString(", ")
+		}
+		w.writePath(q.projectedFields[i])
+		projected++
+	}
+	if projected == 0 {
+		w.buf.WriteByte('*')
+	}
+	w.buf.WriteString(" FROM ")
+	w.writeTable(q.table, q.index)
+	w.writeWhere(q)
+	w.writeOrderBy(q)
+	return w.statement()
+}
+
+// NewDeleteStatement builds a PartiQL DELETE Statement using current QueryBuilder instance values. Non-key
+// conditions are used as conditions of the deletion.
+//
+// e.g. DELETE FROM "Graph" WHERE "pk" = ? AND "sk" = ? AND "status" = ?
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by ExecuteStatement API.
+func NewDeleteStatement(q *QueryBuilder) (Statement, error) {
+	if err := q.Validate(); err != nil {
+		return Statement{}, err
+	} else if q.parallelDegree > 0 {
+		return Statement{}, newValidationError(ErrInvalidOption, "",
+			"degree of parallelism is only available for Scan operations")
+	} else if q.index != nil {
+		return Statement{}, newValidationError(ErrInvalidOption, "", "DELETE statements do not accept indexes")
+	} else if err = validateKeyConditions(keyConditions(q.conditions)); err != nil {
+		return Statement{}, err
+	}
+	w := statementWriter{}
+	w.buf.WriteString("DELETE FROM ")
+	w.writeTable(q.table, nil)
+	w.writeWhere(q)
+	return w.statement()
+}
+
+// NewStatementInput builds a dynamodb.ExecuteStatementInput using the given Statement.
+func NewStatementInput(s Statement) dynamodb.ExecuteStatementInput {
+	return dynamodb.ExecuteStatementInput{
+		Statement:  &s.Query,
+		Parameters: s.Parameters,
+	}
+}
+
+// ExecStatement executes an ExecuteStatement API operation using the given Statement.
+func ExecStatement(ctx context.Context, c *dynamodb.Client, s Statement) (dynamodb.ExecuteStatementOutput, error) {
+	in := NewStatementInput(s)
+	out, err := c.ExecuteStatement(ctx, &in)
+	if err != nil {
+		return dynamodb.ExecuteStatementOutput{}, err
+	}
+	return *out, nil
+}
+
+// Statement builds a PartiQL SELECT Statement using current QueryBuilder instance values.
+//
+// Returns a ValidationError if QueryBuilder values are not accepted by ExecuteStatement API.
+func (q *QueryBuilder) Statement() (Statement, error) {
+	return NewSelectStatement(q)
+}
+
+// ExecStatement executes an ExecuteStatement API operation using a PartiQL SELECT Statement built from current
+// QueryBuilder instance values.
+//
+// Limit, consistency and metrics are set as ExecuteStatement API options. Unlike Query and Scan APIs,
+// ExecuteStatement API paginates using an opaque token (NextToken), hence PageToken is ignored.
+func (q *QueryBuilder) ExecStatement(ctx context.Context, c *dynamodb.Client) (dynamodb.ExecuteStatementOutput,
+	error) {
+	s, err := NewSelectStatement(q)
+	if err != nil {
+		return dynamodb.ExecuteStatementOutput{}, err
+	}
+	in := NewStatementInput(s)
+	in.Limit = &q.limit
+	in.ConsistentRead = &q.isConsistent
+	in.ReturnConsumedCapacity = q.returnMetrics
+	out, err := c.ExecuteStatement(ctx, &in)
+	if err != nil {
+		return dynamodb.ExecuteStatementOutput{}, err
+	}
+	return *out, nil
+}
@@ -0,0 +1,122 @@
+package dynamoql_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSelectStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     *dynamoql.QueryBuilder
+		exp       string
+		expParams []types.AttributeValue
+		expString string
+		expErr    error
+	}{
+		{
+			name:   "Missing table",
+			query:  dynamoql.Select(),
+			expErr: dynamoql.ErrMissingTable,
+		},
+		{
+			name:      "All attributes",
+			query:     dynamoql.Select().From("Graph"),
+			exp:       `SELECT * FROM "Graph"`,
+			expString: `SELECT * FROM "Graph"`,
+		},
+		{
+			name:      "Dotted attribute names",
+			query:     dynamoql.Select("legacy.name", dynamoql.Path("address.city")).From("Graph"),
+			exp:       `SELECT "legacy.name", "address"."city" FROM "Graph"`,
+			expString: `SELECT "legacy.name", "address"."city" FROM "Graph"`,
+		},
+		{
+			name: "Keys and filters",
+			query: dynamoql.MustParseQuery("SELECT a, address.city FROM Graph USE INDEX GsiOverload "+
+				"WHERE pk = ? AND begins_with(sk, ?) FILTER amount > ? OR status IN ('A', 'B') ORDER DESC",
+				"STUDENT#123", "CLASSROOM#", 100),
+			exp: `SELECT "a", "address"."city" FROM "Graph"."GsiOverload" WHERE "pk" = ? AND begins_with("sk", ?) ` +
+				`AND ("amount" > ? OR "status" IN [?, ?]) ORDER BY "sk" DESC`,
+			expParams: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "STUDENT#123"},
+				&types.AttributeValueMemberS{Value: "CLASSROOM#"},
+				&types.AttributeValueMemberN{Value: "100"},
+				&types.AttributeValueMemberS{Value: "A"},
+				&types.AttributeValueMemberS{Value: "B"},
+			},
+			expString: `SELECT "a", "address"."city" FROM "Graph"."GsiOverload" WHERE "pk" = 'STUDENT#123' AND ` +
+				`begins_with("sk", 'CLASSROOM#') AND ("amount" > 100 OR "status" IN ['A', 'B']) ORDER BY "sk" DESC`,
+		},
+		{
+			name: "Functions",
+			query: dynamoql.MustParseQuery("SELECT * FROM t FILTER attribute_exists(a) AND NOT attribute_not_exists(b) "+
+				"AND size(items[0].tags) BETWEEN 1 AND ? AND balance <= credit_limit AND name = 'O''Neil'", 3),
+			exp: `SELECT * FROM "t" WHERE "a" IS NOT MISSING AND NOT ("b" IS MISSING) AND ` +
+				`size("items"[0]."tags") BETWEEN ? AND ? AND "balance" <= "credit_limit" AND "name" = ?`,
+			expParams: []types.AttributeValue{
+				&types.AttributeValueMemberN{Value: "1"},
+				&types.AttributeValueMemberN{Value: "3"},
+				&types.AttributeValueMemberS{Value: "O'Neil"},
+			},
+			expString: `SELECT * FROM "t" WHERE "a" IS NOT MISSING AND NOT ("b" IS MISSING) AND ` +
+				`size("items"[0]."tags") BETWEEN 1 AND 3 AND "balance" <= "credit_limit" AND "name" = 'O''Neil'`,
+		},
+		{
+			name: "Negated",
+			query: dynamoql.Select().From("t").Negate().Or().Where(dynamoql.Condition{
+				Operator: dynamoql.Equals,
+				Field:    `weird"name?`,
+				Value:    true,
+			}, dynamoql.Condition{
+				Operator: dynamoql.Contains,
+				Field:    "tags",
+				Value:    []string{"foo"},
+			}),
+			exp: `SELECT * FROM "t" WHERE NOT ("weird""name?" = ? OR contains("tags", ?))`,
+			expParams: []types.AttributeValue{
+				&types.AttributeValueMemberBOOL{Value: true},
+				&types.AttributeValueMemberSS{Value: []string{"foo"}},
+			},
+			expString: `SELECT * FROM "t" WHERE NOT ("weird""name?" = true OR contains("tags", <<'foo'>>))`,
+		},
+		{
+			name: "Unsupported value",
+			query: dynamoql.Select().From("t").Where(dynamoql.Condition{
+				Operator: dynamoql.Equals,
+				Field:    "foo",
+				Value:    struct{}{},
+			}),
+			expErr: dynamoql.ErrInvalidCondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.query.Statement()
+			assert.ErrorIs(t, err, tt.expErr)
+			if tt.expErr != nil {
+				return
+			}
+			assert.Equal(t, tt.exp, s.Query)
+			assert.Equal(t, tt.expParams, s.Parameters)
+			assert.Equal(t, tt.expString, s.String())
+		})
+	}
+}
+
+func TestNewDeleteStatement(t *testing.T) {
+	s, err := dynamoql.NewDeleteStatement(dynamoql.MustParseQuery(
+		"SELECT * FROM Graph WHERE pk = ? AND sk = ? FILTER attribute_exists(pk)", "STUDENT#123", "STUDENT#123"))
+	require.NoError(t, err)
+	assert.Equal(t, `DELETE FROM "Graph" WHERE "pk" = ? AND "sk" = ? AND "pk" IS NOT MISSING`, s.Query)
+	assert.Len(t, s.Parameters, 2)
+
+	_, err = dynamoql.NewDeleteStatement(dynamoql.MustParseQuery("SELECT * FROM Graph FILTER status = 'ACTIVE'"))
+	assert.ErrorIs(t, err, dynamoql.ErrMissingKeyCondition)
+	_, err = dynamoql.NewDeleteStatement(dynamoql.MustParseQuery("SELECT * FROM Graph USE INDEX Gsi WHERE pk = 1"))
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidOption)
+}
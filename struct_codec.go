@@ -0,0 +1,692 @@
+package dynamoql
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// StructTagName name of the struct tag used by Marshal and Unmarshal.
+//
+// Tags are formatted as follows: `dynamoql:"{ATTRIBUTE_NAME},{OPTION_0},{OPTION_N}"`. Available options are:
+//
+//   - omitempty: omits the attribute if the field holds its zero value (or an empty slice or map).
+//   - set: stores slices of strings, numbers or binaries as Amazon DynamoDB sets (SS, NS or BS). Nil slices are stored
+//     as NULL and empty slices return ErrEmptySet (Amazon DynamoDB does not accept empty sets) unless omitempty is set.
+//   - unixtime: stores time.Time as Unix epoch seconds (N, TimeUnix), e.g. Amazon DynamoDB TTL attributes.
+//   - unixmilli: stores time.Time as Unix epoch milliseconds (N, TimeUnixMilli).
+//   - rfc3339nano: stores time.Time as a string with nanoseconds precision (S, TimeRFC3339Nano).
+//   - composite={PREFIX}: stores strings as composite keys (NewCompositeKey), e.g. composite=STUDENT.
+//   - key: marks the attribute as part of the primary key. Used by dynamoqlgen to generate GetKeys.
+//   - schema: marks a field which writes the schema name into DefaultSchemaField attribute. The attribute name of the
+//     tag is used as schema name, if empty, GetName is used if the struct implements NodeSchema.
+//
+// Fields tagged with "-" are ignored.
+const StructTagName = "dynamoql"
+
+var (
+	// ErrUnsupportedType the given Go type cannot be converted into an Amazon DynamoDB attribute.
+	ErrUnsupportedType = errors.New("dynamoql: Unsupported type")
+	// ErrInvalidStructTag a struct tag contains an unknown option or an option not supported by the field type.
+	ErrInvalidStructTag = errors.New("dynamoql: Invalid struct tag")
+	// ErrInvalidSchema the schema name of an item does not match the schema name of a struct.
+	ErrInvalidSchema = errors.New("dynamoql: Invalid schema")
+)
+
+// AttributeError a failure converting a specific attribute.
+type AttributeError struct {
+	Attribute string
+	Err       error
+}
+
+var _ error = AttributeError{}
+
+func (e AttributeError) Error() string {
+	return "dynamoql: Attribute " + strconv.Quote(e.Attribute) + ": " + e.Err.Error()
+}
+
+// Unwrap retrieves the underlying error.
+func (e AttributeError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	attributeValueType = reflect.TypeOf((*types.AttributeValue)(nil)).Elem()
+	marshalerType      = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	nodeSchemaType     = reflect.TypeOf((*NodeSchema)(nil)).Elem()
+	timeType           = reflect.TypeOf(time.Time{})
+	byteSliceType      = reflect.TypeOf([]byte(nil))
+)
+
+// timeEncodingOptions TimeEncoding of each struct tag time option.
+var timeEncodingOptions = map[string]TimeEncoding{
+	"unixtime":    TimeUnix,
+	"unixmilli":   TimeUnixMilli,
+	"rfc3339nano": TimeRFC3339Nano,
+}
+
+// fieldOptions options of a struct field, parsed from its struct tag.
+type fieldOptions struct {
+	omitEmpty    bool
+	key          bool
+	set          bool
+	timeEncoding TimeEncoding
+	hasComposite bool
+	composite    string
+}
+
+type fieldCodec struct {
+	name  string
+	index []int
+	opts  fieldOptions
+}
+
+// structCodec fields of a struct type to be converted from/into Amazon DynamoDB attributes.
+type structCodec struct {
+	fields    []fieldCodec
+	hasSchema bool
+	schema    string
+}
+
+// structCodecs cache of structCodec(s) for each struct type.
+var structCodecs sync.Map
+
+// getStructCodec retrieves the structCodec of t, building it if not cached yet.
+func getStructCodec(t reflect.Type) (*structCodec, error) {
+	if c, ok := structCodecs.Load(t); ok {
+		return c.(*structCodec), nil
+	}
+	c := &structCodec{}
+	if err := c.build(t, nil); err != nil {
+		return nil, err
+	}
+	if !c.hasSchema {
+		return storeStructCodec(t, c), nil
+	}
+	if c.schema == "" && t.Implements(nodeSchemaType) {
+		c.schema = reflect.Zero(t).Interface().(NodeSchema).GetName()
+	} else if c.schema == "" && reflect.PointerTo(t).Implements(nodeSchemaType) {
+		c.schema = reflect.New(t).Interface().(NodeSchema).GetName()
+	}
+	if c.schema == "" {
+		return nil, AttributeError{Attribute: DefaultSchemaField, Err: ErrInvalidStructTag}
+	}
+	return storeStructCodec(t, c), nil
+}
+
+func storeStructCodec(t reflect.Type, c *structCodec) *structCodec {
+	actual, _ := structCodecs.LoadOrStore(t, c)
+	return actual.(*structCodec)
+}
+
+// build registers exported fields of t, flattening untagged embedded structs.
+func (c *structCodec) build(t reflect.Type, index []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup(StructTagName)
+		if tag == "-" {
+			continue
+		}
+		name, opts, isSchema, err := parseStructTag(tag, f.Type)
+		if err != nil {
+			return AttributeError{Attribute: f.Name, Err: err}
+		}
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+		if isSchema {
+			c.hasSchema = true
+			c.schema = name
+			continue
+		} else if f.Anonymous && f.Type.Kind() == reflect.Struct && (!hasTag || name == "") {
+			if err = c.build(f.Type, fieldIndex); err != nil {
+				return err
+			}
+			continue
+		} else if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		c.fields = append(c.fields, fieldCodec{
+			name:  name,
+			index: fieldIndex,
+			opts:  opts,
+		})
+	}
+	return nil
+}
+
+// parseStructTag parses the given struct tag, verifying options are supported by the field type t.
+func parseStructTag(tag string, t reflect.Type) (name string, opts fieldOptions, isSchema bool, err error) {
+	spl := strings.Split(tag, ",")
+	name = spl[0]
+	elemType := t
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	for _, opt := range spl[1:] {
+		switch {
+		case opt == "omitempty":
+			opts.omitEmpty = true
+		case opt == "key":
+			opts.key = true
+		case opt == "schema":
+			isSchema = true
+		case opt == "set":
+			opts.set = true
+			if !isSetType(elemType) {
+				return "", fieldOptions{}, false, ErrInvalidStructTag
+			}
+		case opt == "unixtime" || opt == "unixmilli" || opt == "rfc3339nano":
+			opts.timeEncoding = timeEncodingOptions[opt]
+			if elemType != timeType {
+				return "", fieldOptions{}, false, ErrInvalidStructTag
+			}
+		case strings.HasPrefix(opt, "composite="):
+			opts.hasComposite = true
+			opts.composite = strings.TrimPrefix(opt, "composite=")
+			if elemType.Kind() != reflect.String || elemType == decimalType {
+				return "", fieldOptions{}, false, ErrInvalidStructTag
+			}
+		default:
+			return "", fieldOptions{}, false, ErrInvalidStructTag
+		}
+	}
+	return name, opts, isSchema, nil
+}
+
+// isSetType indicates if t can be stored as an Amazon DynamoDB set.
+func isSetType(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	} else if t.Elem() == byteSliceType {
+		return true
+	}
+	switch t.Elem().Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isEmptyValue indicates if v holds its zero value, an empty slice or an empty map.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// encodeStruct converts the given struct into an Amazon DynamoDB map using its structCodec.
+func encodeStruct(v reflect.Value) (map[string]types.AttributeValue, error) {
+	c, err := getStructCodec(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]types.AttributeValue, len(c.fields)+1)
+	if c.hasSchema {
+		m[DefaultSchemaField] = &types.AttributeValueMemberS{Value: c.schema}
+	}
+	for _, f := range c.fields {
+		fv := v.FieldByIndex(f.index)
+		if f.opts.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		attr, err := encodeValue(fv, f.opts)
+		if err != nil {
+			return nil, AttributeError{Attribute: f.name, Err: err}
+		}
+		m[f.name] = attr
+	}
+	return m, nil
+}
+
+// encodeValue converts v into an Amazon DynamoDB attribute.
+func encodeValue(v reflect.Value, opts fieldOptions) (types.AttributeValue, error) {
+	if !v.IsValid() {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+	t := v.Type()
+	switch {
+	case t.Implements(attributeValueType):
+		if (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) && v.IsNil() {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		}
+		return v.Interface().(types.AttributeValue), nil
+	case isNumberType(t):
+		return MarshalAttribute(v.Interface())
+	case v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer:
+		if v.IsNil() {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		} else if v.Kind() == reflect.Pointer && t.Implements(marshalerType) {
+			return encodeMarshaler(v.Interface().(Marshaler))
+		}
+		return encodeValue(v.Elem(), opts)
+	case t == timeType:
+		return encodeTime(v.Interface().(time.Time), opts), nil
+	case t.Implements(marshalerType):
+		return encodeMarshaler(v.Interface().(Marshaler))
+	case v.CanAddr() && reflect.PointerTo(t).Implements(marshalerType):
+		return encodeMarshaler(v.Addr().Interface().(Marshaler))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if opts.hasComposite {
+			return &types.AttributeValueMemberS{Value: NewCompositeKey(opts.composite, v.String())}, nil
+		}
+		return &types.AttributeValueMemberS{Value: v.String()}, nil
+	case reflect.Bool:
+		return &types.AttributeValueMemberBOOL{Value: v.Bool()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		n, err := formatNumber(v)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberN{Value: n}, nil
+	case reflect.Slice:
+		if opts.set {
+			return encodeSet(v)
+		} else if v.IsNil() {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		} else if t.Elem().Kind() == reflect.Uint8 {
+			return &types.AttributeValueMemberB{Value: v.Bytes()}, nil
+		}
+		return encodeList(v)
+	case reflect.Array:
+		return encodeList(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		} else if t.Key().Kind() != reflect.String {
+			return nil, ErrUnsupportedType
+		}
+		m := make(map[string]types.AttributeValue, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			attr, err := encodeValue(iter.Value(), fieldOptions{})
+			if err != nil {
+				return nil, AttributeError{Attribute: iter.Key().String(), Err: err}
+			}
+			m[iter.Key().String()] = attr
+		}
+		return &types.AttributeValueMemberM{Value: m}, nil
+	case reflect.Struct:
+		m, err := encodeStruct(v)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: m}, nil
+	default:
+		return nil, ErrUnsupportedType
+	}
+}
+
+func encodeMarshaler(m Marshaler) (types.AttributeValue, error) {
+	item, err := m.MarshalDynamoDB()
+	if err != nil {
+		return nil, err
+	}
+	return &types.AttributeValueMemberM{Value: item}, nil
+}
+
+func encodeTime(t time.Time, opts fieldOptions) types.AttributeValue {
+	return FormatTime(t, opts.timeEncoding)
+}
+
+// formatNumber formats the given numeric value. Returns ErrUnsupportedType if v is either NaN or infinite as Amazon
+// DynamoDB does not support them. Decimal values are validated using ValidateNumber.
+func formatNumber(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", ErrUnsupportedType
+		}
+		return strconv.FormatFloat(f, 'f', -1, v.Type().Bits()), nil
+	case reflect.String:
+		if v.Type() != decimalType {
+			return "", ErrUnsupportedType
+		}
+		return v.String(), ValidateNumber(v.String())
+	default:
+		return "", ErrUnsupportedType
+	}
+}
+
+func encodeList(v reflect.Value) (types.AttributeValue, error) {
+	list := make([]types.AttributeValue, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		attr, err := encodeValue(v.Index(i), fieldOptions{})
+		if err != nil {
+			return nil, AttributeError{Attribute: "[" + strconv.Itoa(i) + "]", Err: err}
+		} else if attr == nil {
+			attr = &types.AttributeValueMemberNULL{Value: true}
+		}
+		list = append(list, attr)
+	}
+	return &types.AttributeValueMemberL{Value: list}, nil
+}
+
+// encodeSet converts the given slice into an Amazon DynamoDB set. Nil slices are converted into NULL.
+//
+// Returns ErrEmptySet if v is empty as Amazon DynamoDB does not accept empty sets, use omitempty to omit them instead.
+func encodeSet(v reflect.Value) (types.AttributeValue, error) {
+	if v.IsNil() {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	} else if v.Len() == 0 {
+		return nil, ErrEmptySet
+	}
+	switch {
+	case v.Type().Elem() == byteSliceType:
+		set := make([][]byte, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			set = append(set, v.Index(i).Bytes())
+		}
+		return &types.AttributeValueMemberBS{Value: set}, nil
+	case v.Type().Elem().Kind() == reflect.String && v.Type().Elem() != decimalType:
+		set := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			set = append(set, v.Index(i).String())
+		}
+		return &types.AttributeValueMemberSS{Value: set}, nil
+	default:
+		set := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			n, err := formatNumber(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			set = append(set, n)
+		}
+		return &types.AttributeValueMemberNS{Value: set}, nil
+	}
+}
+
+// decodeStruct converts the given Amazon DynamoDB map into v (an addressable struct) using its structCodec.
+func decodeStruct(m map[string]types.AttributeValue, v reflect.Value) error {
+	c, err := getStructCodec(v.Type())
+	if err != nil {
+		return err
+	}
+	// items without schema attribute (e.g. projected items) are accepted
+	if attr, ok := m[DefaultSchemaField]; ok && c.hasSchema {
+		if name, _ := ParseString(attr); name != c.schema {
+			return AttributeError{Attribute: DefaultSchemaField, Err: ErrInvalidSchema}
+		}
+	}
+	for _, f := range c.fields {
+		attr, ok := m[f.name]
+		if !ok {
+			continue
+		}
+		if err = decodeValue(attr, v.FieldByIndex(f.index), f.opts); err != nil {
+			return AttributeError{Attribute: f.name, Err: err}
+		}
+	}
+	return nil
+}
+
+// decodeValue converts the given Amazon DynamoDB attribute into v (a settable value).
+func decodeValue(attr types.AttributeValue, v reflect.Value, opts fieldOptions) error {
+	if _, ok := attr.(*types.AttributeValueMemberNULL); ok || attr == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	t := v.Type()
+	switch {
+	case t.Implements(attributeValueType):
+		rv := reflect.ValueOf(attr)
+		if !rv.Type().AssignableTo(t) {
+			return ErrCannotCastAttribute
+		}
+		v.Set(rv)
+		return nil
+	case isNumberType(t):
+		n, err := parseNumberValue(attr, t)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(n))
+		return nil
+	case v.Kind() == reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		if t.Implements(unmarshalerType) {
+			return decodeUnmarshaler(attr, v.Interface().(Unmarshaler))
+		}
+		return decodeValue(attr, v.Elem(), opts)
+	case t == timeType:
+		tm, err := decodeTime(attr, opts)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(tm))
+		return nil
+	case reflect.PointerTo(t).Implements(unmarshalerType):
+		return decodeUnmarshaler(attr, v.Addr().Interface().(Unmarshaler))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s, err := ParseString(attr)
+		if err != nil {
+			return err
+		} else if opts.hasComposite {
+			s = ParseCompositeKey(s)
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := ParseBool(attr)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := attr.(*types.AttributeValueMemberN)
+		if !ok {
+			return ErrCannotCastAttribute
+		}
+		i, err := strconv.ParseInt(n.Value, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := attr.(*types.AttributeValueMemberN)
+		if !ok {
+			return ErrCannotCastAttribute
+		}
+		u, err := strconv.ParseUint(n.Value, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		n, ok := attr.(*types.AttributeValueMemberN)
+		if !ok {
+			return ErrCannotCastAttribute
+		}
+		f, err := strconv.ParseFloat(n.Value, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		return decodeSlice(attr, v)
+	case reflect.Array:
+		list, ok := attr.(*types.AttributeValueMemberL)
+		if !ok {
+			return ErrCannotCastAttribute
+		}
+		for i := 0; i < v.Len() && i < len(list.Value); i++ {
+			if err := decodeValue(list.Value[i], v.Index(i), fieldOptions{}); err != nil {
+				return AttributeError{Attribute: "[" + strconv.Itoa(i) + "]", Err: err}
+			}
+		}
+	case reflect.Map:
+		m, ok := attr.(*types.AttributeValueMemberM)
+		if !ok {
+			return ErrCannotCastAttribute
+		} else if t.Key().Kind() != reflect.String {
+			return ErrUnsupportedType
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(t, len(m.Value)))
+		}
+		for k, elemAttr := range m.Value {
+			elem := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(elemAttr, elem, fieldOptions{}); err != nil {
+				return AttributeError{Attribute: k, Err: err}
+			}
+			v.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), elem)
+		}
+	case reflect.Struct:
+		m, ok := attr.(*types.AttributeValueMemberM)
+		if !ok {
+			return ErrCannotCastAttribute
+		}
+		return decodeStruct(m.Value, v)
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			return ErrUnsupportedType
+		}
+		val, err := decodeInterface(attr)
+		if err != nil {
+			return err
+		} else if val != nil {
+			v.Set(reflect.ValueOf(val))
+		}
+	default:
+		return ErrUnsupportedType
+	}
+	return nil
+}
+
+func decodeUnmarshaler(attr types.AttributeValue, u Unmarshaler) error {
+	m, ok := attr.(*types.AttributeValueMemberM)
+	if !ok {
+		return ErrCannotCastAttribute
+	}
+	return u.UnmarshalDynamoDB(m.Value)
+}
+
+func decodeTime(attr types.AttributeValue, opts fieldOptions) (time.Time, error) {
+	if opts.timeEncoding == "" {
+		return ParseTime(attr)
+	}
+	return ParseTimeAs(attr, opts.timeEncoding)
+}
+
+// decodeSlice converts either an Amazon DynamoDB list, set or binary into v.
+func decodeSlice(attr types.AttributeValue, v reflect.Value) error {
+	t := v.Type()
+	var elems []types.AttributeValue
+	switch a := attr.(type) {
+	case *types.AttributeValueMemberB:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return ErrCannotCastAttribute
+		}
+		v.SetBytes(append([]byte(nil), a.Value...))
+		return nil
+	case *types.AttributeValueMemberL:
+		elems = a.Value
+	case *types.AttributeValueMemberSS:
+		elems = make([]types.AttributeValue, 0, len(a.Value))
+		for _, s := range a.Value {
+			elems = append(elems, &types.AttributeValueMemberS{Value: s})
+		}
+	case *types.AttributeValueMemberNS:
+		elems = make([]types.AttributeValue, 0, len(a.Value))
+		for _, n := range a.Value {
+			elems = append(elems, &types.AttributeValueMemberN{Value: n})
+		}
+	case *types.AttributeValueMemberBS:
+		elems = make([]types.AttributeValue, 0, len(a.Value))
+		for _, b := range a.Value {
+			elems = append(elems, &types.AttributeValueMemberB{Value: b})
+		}
+	default:
+		return ErrCannotCastAttribute
+	}
+	slice := reflect.MakeSlice(t, len(elems), len(elems))
+	for i := range elems {
+		if err := decodeValue(elems[i], slice.Index(i), fieldOptions{}); err != nil {
+			return AttributeError{Attribute: "[" + strconv.Itoa(i) + "]", Err: err}
+		}
+	}
+	v.Set(slice)
+	return nil
+}
+
+// decodeInterface converts the given Amazon DynamoDB attribute into its natural Go type. Numbers are converted into
+// Decimal to keep their precision, lists into []interface{} and maps into map[string]interface{}.
+func decodeInterface(attr types.AttributeValue) (interface{}, error) {
+	switch a := attr.(type) {
+	case *types.AttributeValueMemberS:
+		return a.Value, nil
+	case *types.AttributeValueMemberN:
+		return ParseDecimal(a)
+	case *types.AttributeValueMemberB:
+		return a.Value, nil
+	case *types.AttributeValueMemberBOOL:
+		return a.Value, nil
+	case *types.AttributeValueMemberNULL:
+		return nil, nil
+	case *types.AttributeValueMemberSS:
+		return a.Value, nil
+	case *types.AttributeValueMemberNS:
+		set := make([]Decimal, 0, len(a.Value))
+		for _, n := range a.Value {
+			if err := ValidateNumber(n); err != nil {
+				return nil, err
+			}
+			set = append(set, Decimal(n))
+		}
+		return set, nil
+	case *types.AttributeValueMemberBS:
+		return a.Value, nil
+	case *types.AttributeValueMemberL:
+		list := make([]interface{}, 0, len(a.Value))
+		for i := range a.Value {
+			elem, err := decodeInterface(a.Value[i])
+			if err != nil {
+				return nil, AttributeError{Attribute: "[" + strconv.Itoa(i) + "]", Err: err}
+			}
+			list = append(list, elem)
+		}
+		return list, nil
+	case *types.AttributeValueMemberM:
+		m := make(map[string]interface{}, len(a.Value))
+		for k, elemAttr := range a.Value {
+			elem, err := decodeInterface(elemAttr)
+			if err != nil {
+				return nil, AttributeError{Attribute: k, Err: err}
+			}
+			m[k] = elem
+		}
+		return m, nil
+	default:
+		return nil, ErrCannotCastAttribute
+	}
+}
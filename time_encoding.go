@@ -0,0 +1,88 @@
+package dynamoql
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TimeEncoding format used to store time.Time values as Amazon DynamoDB attributes.
+type TimeEncoding string
+
+const (
+	// TimeRFC3339 stores time as a string (S) formatted with time.RFC3339.
+	TimeRFC3339 TimeEncoding = "RFC3339"
+	// TimeRFC3339Nano stores time as a string (S) formatted with time.RFC3339Nano.
+	TimeRFC3339Nano TimeEncoding = "RFC3339_NANO"
+	// TimeUnix stores time as a number (N) of seconds elapsed since the Unix epoch. Amazon DynamoDB TTL attributes
+	// MUST use this encoding.
+	TimeUnix TimeEncoding = "UNIX"
+	// TimeUnixMilli stores time as a number (N) of milliseconds elapsed since the Unix epoch.
+	TimeUnixMilli TimeEncoding = "UNIX_MILLI"
+)
+
+// unixMilliThreshold epoch values greater than (or equal to) this threshold are handled as milliseconds by ParseTime.
+// Seconds would represent a year after 5000 while milliseconds represent a date after March 1973.
+const unixMilliThreshold = 1e11
+
+// FormatTime converts t into an Amazon DynamoDB attribute using the given encoding. DefaultTimeFormat is used if enc
+// is empty.
+func FormatTime(t time.Time, enc TimeEncoding) types.AttributeValue {
+	switch enc {
+	case TimeRFC3339:
+		return &types.AttributeValueMemberS{Value: t.Format(time.RFC3339)}
+	case TimeRFC3339Nano:
+		return &types.AttributeValueMemberS{Value: t.Format(time.RFC3339Nano)}
+	case TimeUnix:
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.Unix(), 10)}
+	case TimeUnixMilli:
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.UnixMilli(), 10)}
+	default:
+		return &types.AttributeValueMemberS{Value: t.Format(DefaultTimeFormat)}
+	}
+}
+
+// ParseTimeAs converts the given Amazon DynamoDB attribute into time.Time using the given encoding. Unix encodings
+// return time in UTC. DefaultTimeFormat is used if enc is empty.
+func ParseTimeAs(v types.AttributeValue, enc TimeEncoding) (time.Time, error) {
+	switch enc {
+	case TimeUnix, TimeUnixMilli:
+		data, ok := v.(*types.AttributeValueMemberN)
+		if !ok {
+			return time.Time{}, ErrCannotCastAttribute
+		}
+		n, err := strconv.ParseInt(data.Value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		} else if enc == TimeUnixMilli {
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+
+	data, ok := v.(*types.AttributeValueMemberS)
+	if !ok {
+		return time.Time{}, ErrCannotCastAttribute
+	}
+	switch enc {
+	case TimeRFC3339:
+		return time.Parse(time.RFC3339, data.Value)
+	case TimeRFC3339Nano:
+		return time.Parse(time.RFC3339Nano, data.Value)
+	default:
+		return time.Parse(DefaultTimeFormat, data.Value)
+	}
+}
+
+// FormatTTL converts t into an Amazon DynamoDB Time To Live attribute (number of seconds elapsed since the Unix
+// epoch).
+func FormatTTL(t time.Time) types.AttributeValue {
+	return FormatTime(t, TimeUnix)
+}
+
+// ParseTTL converts the given Amazon DynamoDB Time To Live attribute (number of seconds elapsed since the Unix epoch)
+// into time.Time (UTC).
+func ParseTTL(v types.AttributeValue) (time.Time, error) {
+	return ParseTimeAs(v, TimeUnix)
+}
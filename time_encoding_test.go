@@ -0,0 +1,84 @@
+package dynamoql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTime(t *testing.T) {
+	tm := time.Date(2022, 5, 1, 10, 30, 0, 123456789, time.UTC)
+	tests := []struct {
+		name string
+		enc  dynamoql.TimeEncoding
+		exp  types.AttributeValue
+	}{
+		{
+			name: "Default",
+			exp:  &types.AttributeValueMemberS{Value: "2022-05-01T10:30:00Z"},
+		},
+		{
+			name: "RFC3339",
+			enc:  dynamoql.TimeRFC3339,
+			exp:  &types.AttributeValueMemberS{Value: "2022-05-01T10:30:00Z"},
+		},
+		{
+			name: "RFC3339 nano",
+			enc:  dynamoql.TimeRFC3339Nano,
+			exp:  &types.AttributeValueMemberS{Value: "2022-05-01T10:30:00.123456789Z"},
+		},
+		{
+			name: "Unix",
+			enc:  dynamoql.TimeUnix,
+			exp:  &types.AttributeValueMemberN{Value: "1651401000"},
+		},
+		{
+			name: "Unix milli",
+			enc:  dynamoql.TimeUnixMilli,
+			exp:  &types.AttributeValueMemberN{Value: "1651401000123"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attr := dynamoql.FormatTime(tm, tt.enc)
+			assert.Equal(t, tt.exp, attr)
+			out, err := dynamoql.ParseTimeAs(attr, tt.enc)
+			assert.NoError(t, err)
+			assert.Equal(t, tm.Truncate(precision(tt.enc)), out)
+		})
+	}
+}
+
+func precision(enc dynamoql.TimeEncoding) time.Duration {
+	switch enc {
+	case dynamoql.TimeRFC3339Nano:
+		return time.Nanosecond
+	case dynamoql.TimeUnixMilli:
+		return time.Millisecond
+	default:
+		return time.Second
+	}
+}
+
+func TestParseTimeAs(t *testing.T) {
+	_, err := dynamoql.ParseTimeAs(dynamoql.FormatAttribute("2022-05-01T10:30:00Z"), dynamoql.TimeUnix)
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+	_, err = dynamoql.ParseTimeAs(dynamoql.FormatAttribute(1651401000), dynamoql.TimeRFC3339)
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+	_, err = dynamoql.ParseTimeAs(&types.AttributeValueMemberN{Value: "1.5"}, dynamoql.TimeUnix)
+	assert.Error(t, err)
+}
+
+func TestFormatTTL(t *testing.T) {
+	tm := time.Date(2022, 5, 1, 10, 30, 0, 0, time.UTC)
+	attr := dynamoql.FormatTTL(tm.Add(time.Millisecond))
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "1651401000"}, attr)
+	out, err := dynamoql.ParseTTL(attr)
+	assert.NoError(t, err)
+	assert.Equal(t, tm, out)
+	_, err = dynamoql.ParseTTL(dynamoql.FormatAttribute(tm))
+	assert.ErrorIs(t, err, dynamoql.ErrCannotCastAttribute)
+}
@@ -0,0 +1,465 @@
+package dynamoql
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// updateClause an Amazon DynamoDB update expression clause.
+type updateClause string
+
+const (
+	updateClauseSet    updateClause = "SET"
+	updateClauseRemove updateClause = "REMOVE"
+	updateClauseAdd    updateClause = "ADD"
+	updateClauseDelete updateClause = "DELETE"
+)
+
+// updateClauses clauses in the order written into update expressions.
+var updateClauses = []updateClause{updateClauseSet, updateClauseRemove, updateClauseAdd, updateClauseDelete}
+
+// updateFunc a SET clause action right-hand operand form.
+type updateFunc uint8
+
+const (
+	updateFuncAssign updateFunc = iota
+	updateFuncIfNotExists
+	updateFuncListAppend
+	updateFuncIncrement
+)
+
+// updateAction a single action of an update expression (e.g. SET #n0 = :v0).
+type updateAction struct {
+	clause updateClause
+	fn     updateFunc
+	field  string
+	value  interface{}
+}
+
+// UpdateBuilder crafts an Amazon DynamoDB update statement ready to be used by UpdateItem API.
+//
+// Actions are written into SET, REMOVE, ADD and DELETE clauses, keeping the order they were added within each clause.
+// Conditions (Where) are written as the condition expression of the update, hence the update is applied only if the
+// existing item matches them.
+//
+// e.g. dynamoql.Update(student.GetKeys()).Table("Graph").Set("name", "Joe").Increment("version", 1)
+type UpdateBuilder struct {
+	negate        bool
+	operator      LogicalOperator
+	table         string
+	keys          map[string]types.AttributeValue
+	actions       []updateAction
+	conditions    []Condition
+	returnValues  types.ReturnValue
+	returnMetrics types.ReturnConsumedCapacity
+}
+
+// NewUpdateBuilder builds an UpdateBuilder instance.
+func NewUpdateBuilder() *UpdateBuilder {
+	return &UpdateBuilder{
+		returnValues:  types.ReturnValueNone,
+		returnMetrics: types.ReturnConsumedCapacityNone,
+	}
+}
+
+// Update builds a new UpdateBuilder instance and sets the primary key of the item to be updated.
+func Update(keys map[string]types.AttributeValue) *UpdateBuilder {
+	return NewUpdateBuilder().Keys(keys)
+}
+
+// Keys sets the primary key (partition and sort keys) of the item to be updated.
+func (u *UpdateBuilder) Keys(keys map[string]types.AttributeValue) *UpdateBuilder {
+	u.keys = keys
+	return u
+}
+
+// Table sets the table to update the item from.
+func (u *UpdateBuilder) Table(table string) *UpdateBuilder {
+	u.table = table
+	return u
+}
+
+func (u *UpdateBuilder) addAction(clause updateClause, fn updateFunc, field string, v interface{}) *UpdateBuilder {
+	u.actions = append(u.actions, updateAction{
+		clause: clause,
+		fn:     fn,
+		field:  field,
+		value:  v,
+	})
+	return u
+}
+
+// Set replaces the attribute value (SET field = v). Use FieldRef to copy the value of another attribute.
+//
+// Field accepts attribute names and document paths (e.g. Path("address.city"), Path("items[2].sku")).
+func (u *UpdateBuilder) Set(field string, v interface{}) *UpdateBuilder {
+	return u.addAction(updateClauseSet, updateFuncAssign, field, v)
+}
+
+// SetIfNotExists sets the attribute value only if the attribute does not exist
+// (SET field = if_not_exists(field, v)).
+func (u *UpdateBuilder) SetIfNotExists(field string, v interface{}) *UpdateBuilder {
+	return u.addAction(updateClauseSet, updateFuncIfNotExists, field, v)
+}
+
+// ListAppend appends v elements to the list attribute (SET field = list_append(field, v)). v MUST be a list
+// attribute value (e.g. types.AttributeValueMemberL).
+func (u *UpdateBuilder) ListAppend(field string, v interface{}) *UpdateBuilder {
+	return u.addAction(updateClauseSet, updateFuncListAppend, field, v)
+}
+
+// Increment adds v to the number attribute (SET field = field + v). Use a negative v to decrement the attribute.
+//
+// The attribute MUST exist, use Add to increment attributes which might not exist.
+func (u *UpdateBuilder) Increment(field string, v interface{}) *UpdateBuilder {
+	return u.addAction(updateClauseSet, updateFuncIncrement, field, v)
+}
+
+// Add either adds v to the number attribute or adds v elements to the set attribute (ADD field v). If the attribute
+// does not exist, it is created with v as initial value.
+func (u *UpdateBuilder) Add(field string, v interface{}) *UpdateBuilder {
+	return u.addAction(updateClauseAdd, updateFuncAssign, field, v)
+}
+
+// Remove removes the given attributes from the item (REMOVE field).
+func (u *UpdateBuilder) Remove(fields ...string) *UpdateBuilder {
+	for i := range fields {
+		u.addAction(updateClauseRemove, updateFuncAssign, fields[i], nil)
+	}
+	return u
+}
+
+// Delete removes v elements from the set attribute (DELETE field v).
+func (u *UpdateBuilder) Delete(field string, v interface{}) *UpdateBuilder {
+	return u.addAction(updateClauseDelete, updateFuncAssign, field, v)
+}
+
+// Where sets conditions statements. The update is applied only if the existing item matches them.
+func (u *UpdateBuilder) Where(c ...Condition) *UpdateBuilder {
+	u.conditions = c
+	return u
+}
+
+// And concatenates Condition(s) with an And operator.
+func (u *UpdateBuilder) And() *UpdateBuilder {
+	u.operator = And
+	return u
+}
+
+// Or concatenates Condition(s) with an Or operator.
+func (u *UpdateBuilder) Or() *UpdateBuilder {
+	u.operator = Or
+	return u
+}
+
+// Negate sets the condition statements output to be opposite.
+func (u *UpdateBuilder) Negate() *UpdateBuilder {
+	u.negate = true
+	return u
+}
+
+// ReturnValues sets the item attributes to be returned by the update operation.
+func (u *UpdateBuilder) ReturnValues(v types.ReturnValue) *UpdateBuilder {
+	u.returnValues = v
+	return u
+}
+
+// Metrics sets the desired metric data from consumed capacity outputs.
+func (u *UpdateBuilder) Metrics(v types.ReturnConsumedCapacity) *UpdateBuilder {
+	u.returnMetrics = v
+	return u
+}
+
+// Validate verifies the current UpdateBuilder instance values are accepted by UpdateItem API, returning a
+// ValidationError if not.
+func (u *UpdateBuilder) Validate() error {
+	if u.table == "" {
+		return newValidationError(ErrMissingTable, "", "table name is required (Table)")
+	} else if len(u.keys) == 0 {
+		return newValidationError(ErrMissingKeys, "", "item primary key is required (Keys)")
+	} else if len(u.actions) == 0 {
+		return newValidationError(ErrInvalidUpdate, "", "at least one update action is required")
+	}
+	for i := range u.actions {
+		if err := u.validateAction(i); err != nil {
+			return err
+		}
+	}
+	return validateWriteConditions(u.operator, u.conditions)
+}
+
+// validateAction verifies the action at position i of the UpdateBuilder actions.
+func (u *UpdateBuilder) validateAction(i int) error {
+	action := u.actions[i]
+	if action.field == "" {
+		return newValidationError(ErrInvalidUpdate, "", "field is required")
+	} else if action.clause != updateClauseRemove && action.value == nil {
+		return newValidationError(ErrInvalidUpdate, action.field, string(action.clause)+" action requires a value")
+	} else if err := validateValue(ErrInvalidUpdate, action.field, action.value); err != nil {
+		return err
+	}
+	if _, isKey := u.keys[fieldElements(action.field)[0].name]; isKey {
+		return newValidationError(ErrInvalidUpdate, action.field, "primary key attributes cannot be updated")
+	}
+	for j := 0; j < i; j++ {
+		if overlapsDocumentPath(u.actions[j].field, action.field) {
+			return newValidationError(ErrInvalidUpdate, action.field,
+				"document path overlaps with "+fieldName(u.actions[j].field)+", only one action per attribute is accepted")
+		}
+	}
+	return nil
+}
+
+// overlapsDocumentPath indicates if either a or b fields is contained by the other one (e.g. address and
+// Path("address.city")).
+func overlapsDocumentPath(a, b string) bool {
+	elemsA, elemsB := fieldElements(a), fieldElements(b)
+	if len(elemsA) > len(elemsB) {
+		elemsA, elemsB = elemsB, elemsA
+	}
+	for i := range elemsA {
+		if elemsA[i] != elemsB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// buildUpdateExpression crafts an Amazon DynamoDB update expression from the given actions, allocating operand
+// placeholders using a.
+//
+// e.g. SET #n0 = :v0, #n1 = if_not_exists(#n1, :v1) REMOVE #n2 ADD #n3 :v2 DELETE #n4 :v3
+//
+// Returns an AttributeError if an action value cannot be converted into an Amazon DynamoDB attribute.
+func buildUpdateExpression(a *placeholderAllocator, actions []updateAction) (*string, error) {
+	buf := strings.Builder{}
+	for _, clause := range updateClauses {
+		written := 0
+		for i := range actions {
+			if actions[i].clause != clause {
+				continue
+			}
+			if written == 0 {
+				if buf.Len() > 0 {
+					buf.WriteByte(' ')
+				}
+				buf.WriteString(string(clause))
+				buf.WriteByte(' ')
+			} else {
+				buf.WriteString(", ")
+			}
+			if err := writeUpdateAction(&buf, a, actions[i]); err != nil {
+				return nil, err
+			}
+			written++
+		}
+	}
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+	return aws.String(buf.String()), nil
+}
+
+func writeUpdateAction(buf *strings.Builder, a *placeholderAllocator, action updateAction) error {
+	path := a.path(action.field)
+	buf.WriteString(path)
+	if action.clause == updateClauseRemove {
+		return nil
+	}
+	operand, err := a.operand(action.value)
+	if err != nil {
+		return AttributeError{Attribute: fieldName(action.field), Err: err}
+	}
+	switch action.clause {
+	case updateClauseAdd, updateClauseDelete:
+		buf.WriteByte(' ')
+		buf.WriteString(operand)
+		return nil
+	}
+	buf.WriteString(" = ")
+	switch action.fn {
+	case updateFuncIfNotExists:
+		buf.WriteString("if_not_exists(")
+		buf.WriteString(path)
+		buf.WriteString(", ")
+		buf.WriteString(operand)
+		buf.WriteByte(')')
+	case updateFuncListAppend:
+		buf.WriteString("list_append(")
+		buf.WriteString(path)
+		buf.WriteString(", ")
+		buf.WriteString(operand)
+		buf.WriteByte(')')
+	case updateFuncIncrement:
+		buf.WriteString(path)
+		buf.WriteString(" + ")
+		buf.WriteString(operand)
+	default:
+		buf.WriteString(operand)
+	}
+	return nil
+}
+
+// NewUpdateInput builds a dynamodb.UpdateItemInput using current UpdateBuilder instance values.
+//
+// Returns a ValidationError if UpdateBuilder values are not accepted by UpdateItem API.
+func NewUpdateInput(u *UpdateBuilder) (dynamodb.UpdateItemInput, error) {
+	if err := u.Validate(); err != nil {
+		return dynamodb.UpdateItemInput{}, err
+	}
+	// placeholders are shared by update and condition expressions to avoid token collisions
+	a := newPlaceholderAllocator()
+	updateExpr, err := buildUpdateExpression(a, u.actions)
+	if err != nil {
+		return dynamodb.UpdateItemInput{}, err
+	}
+	conditionExpr, err := buildExpression(a, u.operator, u.negate, u.conditions)
+	if err != nil {
+		return dynamodb.UpdateItemInput{}, err
+	}
+	return dynamodb.UpdateItemInput{
+		Key:                       u.keys,
+		TableName:                 &u.table,
+		ConditionExpression:       conditionExpr,
+		ExpressionAttributeNames:  a.attributeNames(),
+		ExpressionAttributeValues: a.attributeValues(),
+		ReturnConsumedCapacity:    u.returnMetrics,
+		ReturnValues:              u.returnValues,
+		UpdateExpression:          updateExpr,
+	}, nil
+}
+
+// ExecUpdate executes an UpdateItem API operation.
+//
+// Returns a ValidationError if UpdateBuilder values are not accepted by UpdateItem API and
+// ErrConditionalCheckFailed if the existing item does not match the conditions.
+func (u *UpdateBuilder) ExecUpdate(ctx context.Context, c *dynamodb.Client) (dynamodb.UpdateItemOutput, error) {
+	in, err := NewUpdateInput(u)
+	if err != nil {
+		return dynamodb.UpdateItemOutput{}, err
+	}
+	out, err := c.UpdateItem(ctx, &in)
+	if err != nil {
+		return dynamodb.UpdateItemOutput{}, newWriteError(err)
+	}
+	return *out, nil
+}
+
+// NewUpdateStatement builds a PartiQL UPDATE Statement using current UpdateBuilder instance values.
+//
+// e.g. UPDATE "Graph" SET "name" = ? SET "version" = "version" + ? REMOVE "tmp" WHERE "pk" = ? AND "sk" = ?
+//
+// PartiQL does not support if_not_exists function, hence SetIfNotExists actions are not accepted. ADD and DELETE
+// actions on sets are written as set_add and set_delete functions.
+//
+// Returns a ValidationError if UpdateBuilder values are not accepted by ExecuteStatement API.
+func NewUpdateStatement(u *UpdateBuilder) (Statement, error) {
+	if err := u.Validate(); err != nil {
+		return Statement{}, err
+	}
+	w := statementWriter{}
+	w.buf.WriteString("UPDATE ")
+	w.writeTable(u.table, nil)
+	for _, action := range u.actions {
+		if action.fn == updateFuncIfNotExists {
+			return Statement{}, newValidationError(ErrInvalidUpdate, action.field,
+				"if_not_exists function is not supported by PartiQL statements")
+		}
+		w.buf.WriteByte(' ')
+		w.writeUpdateAction(action)
+	}
+	w.writeItemWhere(u.keys, u.operator, u.negate, u.conditions)
+	return w.statement()
+}
+
+// writeUpdateAction writes a single action as PartiQL SET or REMOVE clause.
+func (w *statementWriter) writeUpdateAction(action updateAction) {
+	if action.clause == updateClauseRemove {
+		w.buf.WriteString(string(updateClauseRemove) + " ")
+		w.writePath(action.field)
+		return
+	}
+	w.buf.WriteString(string(updateClauseSet) + " ")
+	w.writePath(action.field)
+	w.buf.WriteString(" = ")
+	switch {
+	case action.fn == updateFuncListAppend:
+		w.buf.WriteString("list_append(")
+		w.writePath(action.field)
+		w.buf.WriteString(", ")
+		w.writeOperand(action.field, action.value)
+		w.buf.WriteByte(')')
+	case action.clause == updateClauseAdd && isSetAttribute(action.value):
+		w.writeUpdateFunc("set_add", action)
+	case action.clause == updateClauseDelete:
+		w.writeUpdateFunc("set_delete", action)
+	case action.clause == updateClauseAdd || action.fn == updateFuncIncrement:
+		w.writePath(action.field)
+		w.buf.WriteString(" + ")
+		w.writeOperand(action.field, action.value)
+	default:
+		w.writeOperand(action.field, action.value)
+	}
+}
+
+func (w *statementWriter) writeUpdateFunc(fn string, action updateAction) {
+	w.buf.WriteString(fn)
+	w.buf.WriteByte('(')
+	w.writePath(action.field)
+	w.buf.WriteString(", ")
+	w.writeOperand(action.field, action.value)
+	w.buf.WriteByte(')')
+}
+
+// writeKeys writes the given primary key as Equals conditions concatenated with an And operator, sorted by
+// attribute name.
+func (w *statementWriter) writeKeys(keys map[string]types.AttributeValue) {
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for i := range names {
+		if i > 0 {
+			w.buf.WriteString(" " + string(And) + " ")
+		}
+		w.writeIdent(names[i])
+		w.buf.WriteString(" = ?")
+		w.params = append(w.params, keys[names[i]])
+	}
+}
+
+// writeItemWhere writes a WHERE clause matching a single item by its primary key. Conditions are grouped and
+// concatenated to the primary key with an And operator.
+func (w *statementWriter) writeItemWhere(keys map[string]types.AttributeValue, op LogicalOperator, negate bool,
+	c []Condition) {
+	w.buf.WriteString(" WHERE ")
+	w.writeKeys(keys)
+	if len(c) == 0 {
+		return
+	}
+	conditions := Group(op, c...)
+	conditions.Negate = negate
+	w.buf.WriteString(" " + string(And) + " ")
+	w.writeCondition(conditions)
+}
+
+// isSetAttribute indicates if v is formatted as an Amazon DynamoDB set (SS, NS or BS).
+func isSetAttribute(v interface{}) bool {
+	switch FormatAttribute(v).(type) {
+	case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+		return true
+	default:
+		return false
+	}
+}
+
+// Statement builds a PartiQL UPDATE Statement using current UpdateBuilder instance values.
+func (u *UpdateBuilder) Statement() (Statement, error) {
+	return NewUpdateStatement(u)
+}
@@ -0,0 +1,156 @@
+package dynamoql_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUpdateInput(t *testing.T) {
+	keys := Bill{InvoiceID: "1191", BillID: "1"}.GetKeys()
+	u := dynamoql.Update(keys).
+		Table("InvoiceAndBills").
+		Set("status", "PAID").
+		Set(dynamoql.Path("address.city"), "Paris").
+		Set("balance", dynamoql.FieldRef("amount")).
+		SetIfNotExists("created_at", "2022-05-01").
+		ListAppend("events", &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberS{Value: "PAID"},
+		}}).
+		Increment("version", 1).
+		Remove("tmp", dynamoql.Path("items[0]")).
+		Add("tags", []string{"paid"}).
+		Delete("flags", []string{"pending"}).
+		Where(dynamoql.Condition{
+			Operator: dynamoql.Equals,
+			Field:    "status",
+			Value:    "PENDING",
+		}).
+		ReturnValues(types.ReturnValueAllNew).
+		Metrics(types.ReturnConsumedCapacityTotal)
+	in, err := dynamoql.NewUpdateInput(u)
+	require.NoError(t, err)
+	assert.Equal(t, keys, in.Key)
+	assert.Equal(t, "InvoiceAndBills", *in.TableName)
+	assert.Equal(t, types.ReturnValueAllNew, in.ReturnValues)
+	assert.Equal(t, types.ReturnConsumedCapacityTotal, in.ReturnConsumedCapacity)
+	assert.Equal(t, "SET #n0 = :v0, #n1.#n2 = :v1, #n3 = #n4, #n5 = if_not_exists(#n5, :v2), "+
+		"#n6 = list_append(#n6, :v3), #n7 = #n7 + :v4 REMOVE #n8, #n9[0] ADD #n10 :v5 DELETE #n11 :v6",
+		*in.UpdateExpression)
+	assert.Equal(t, "#n0 = :v7", *in.ConditionExpression)
+	assert.Equal(t, map[string]string{
+		"#n0":  "status",
+		"#n1":  "address",
+		"#n2":  "city",
+		"#n3":  "balance",
+		"#n4":  "amount",
+		"#n5":  "created_at",
+		"#n6":  "events",
+		"#n7":  "version",
+		"#n8":  "tmp",
+		"#n9":  "items",
+		"#n10": "tags",
+		"#n11": "flags",
+	}, in.ExpressionAttributeNames)
+	assert.Len(t, in.ExpressionAttributeValues, 8)
+	assert.Equal(t, &types.AttributeValueMemberSS{Value: []string{"pending"}}, in.ExpressionAttributeValues[":v6"])
+}
+
+func TestUpdateBuilder_Validate(t *testing.T) {
+	keys := Bill{InvoiceID: "1191", BillID: "1"}.GetKeys()
+	tests := []struct {
+		name   string
+		update *dynamoql.UpdateBuilder
+		exp    error
+	}{
+		{
+			name:   "Missing table",
+			update: dynamoql.Update(keys).Set("status", "PAID"),
+			exp:    dynamoql.ErrMissingTable,
+		},
+		{
+			name:   "Missing keys",
+			update: dynamoql.Update(nil).Table("sample").Set("status", "PAID"),
+			exp:    dynamoql.ErrMissingKeys,
+		},
+		{
+			name:   "Missing actions",
+			update: dynamoql.Update(keys).Table("sample"),
+			exp:    dynamoql.ErrInvalidUpdate,
+		},
+		{
+			name:   "Missing value",
+			update: dynamoql.Update(keys).Table("sample").Set("status", nil),
+			exp:    dynamoql.ErrInvalidUpdate,
+		},
+		{
+			name:   "Unsupported value",
+			update: dynamoql.Update(keys).Table("sample").Set("status", struct{}{}),
+			exp:    dynamoql.ErrInvalidUpdate,
+		},
+		{
+			name:   "Key attribute",
+			update: dynamoql.Update(keys).Table("sample").Set("PK", "foo"),
+			exp:    dynamoql.ErrInvalidUpdate,
+		},
+		{
+			name:   "Overlapping paths",
+			update: dynamoql.Update(keys).Table("sample").Set(dynamoql.Path("address.city"), "Paris").Remove("address"),
+			exp:    dynamoql.ErrInvalidUpdate,
+		},
+		{
+			name:   "Non overlapping paths",
+			update: dynamoql.Update(keys).Table("sample").Set(dynamoql.Path("address.city"), "Paris").Remove("address_old"),
+		},
+		{
+			name:   "Literal dotted name",
+			update: dynamoql.Update(keys).Table("sample").Set("address.city", "Paris").Remove("address"),
+		},
+		{
+			name: "Invalid condition",
+			update: dynamoql.Update(keys).Table("sample").Set("status", "PAID").Where(dynamoql.Condition{
+				Operator: dynamoql.Between,
+				Field:    "amount",
+				Value:    10,
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.update.Validate(), tt.exp)
+		})
+	}
+}
+
+func TestNewUpdateStatement(t *testing.T) {
+	keys := Bill{InvoiceID: "1191", BillID: "1"}.GetKeys()
+	s, err := dynamoql.Update(keys).
+		Table("InvoiceAndBills").
+		Set("status", "PAID").
+		Increment("version", 1).
+		Add("amount", 10).
+		Add("tags", []string{"paid"}).
+		Delete("flags", []string{"pending"}).
+		Remove("tmp").
+		Negate().
+		Where(dynamoql.Condition{
+			Operator: dynamoql.AttributeNotExists,
+			Field:    "deleted_at",
+		}).
+		Statement()
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE "InvoiceAndBills" SET "status" = ? SET "version" = "version" + ? `+
+		`SET "amount" = "amount" + ? SET "tags" = set_add("tags", ?) SET "flags" = set_delete("flags", ?) `+
+		`REMOVE "tmp" WHERE "PK" = ? AND "SK" = ? AND NOT ("deleted_at" IS MISSING)`, s.Query)
+	assert.Equal(t, `UPDATE "InvoiceAndBills" SET "status" = 'PAID' SET "version" = "version" + 1 `+
+		`SET "amount" = "amount" + 10 SET "tags" = set_add("tags", <<'paid'>>) `+
+		`SET "flags" = set_delete("flags", <<'pending'>>) REMOVE "tmp" WHERE "PK" = 'I#1191' AND "SK" = 'B#1' `+
+		`AND NOT ("deleted_at" IS MISSING)`, s.String())
+
+	_, err = dynamoql.Update(keys).Table("InvoiceAndBills").SetIfNotExists("status", "PAID").Statement()
+	assert.ErrorIs(t, err, dynamoql.ErrInvalidUpdate)
+}
@@ -0,0 +1,335 @@
+package dynamoql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// maxTotalSegments maximum number of segments accepted by Scan API.
+	maxTotalSegments = 1000000
+	// maxInOperands maximum number of operands accepted by an In operator.
+	maxInOperands = 100
+	// maxKeyConditions maximum number of key conditions (partition and sort keys).
+	maxKeyConditions = 2
+)
+
+var (
+	// ErrMissingTable the query has no table to read from (From).
+	ErrMissingTable = errors.New("dynamoql: Missing table")
+	// ErrMissingKeyCondition the operation requires at least one key condition.
+	ErrMissingKeyCondition = errors.New("dynamoql: Missing key condition")
+	// ErrInvalidKeyCondition a key condition is not accepted by Amazon DynamoDB key expressions.
+	ErrInvalidKeyCondition = errors.New("dynamoql: Invalid key condition")
+	// ErrInvalidCondition a condition is malformed (e.g. missing field or operands).
+	ErrInvalidCondition = errors.New("dynamoql: Invalid condition")
+	// ErrInvalidLogicalOperator the logical operator cannot be applied to the given conditions.
+	ErrInvalidLogicalOperator = errors.New("dynamoql: Invalid logical operator")
+	// ErrMissingKeys the operation requires the primary key of an item.
+	ErrMissingKeys = errors.New("dynamoql: Missing item keys")
+	// ErrMissingSchema the operation requires a schema to write (e.g. PutBuilder.Schema).
+	ErrMissingSchema = errors.New("dynamoql: Missing schema")
+	// ErrMissingQuery the operation requires a QueryBuilder to read items from (e.g. HydrateEdges).
+	ErrMissingQuery = errors.New("dynamoql: Missing query")
+	// ErrInvalidUpdate an update action is malformed or not accepted by Amazon DynamoDB update expressions.
+	ErrInvalidUpdate = errors.New("dynamoql: Invalid update")
+	// ErrInvalidOption a QueryBuilder option is either out of range or not supported by the operation.
+	ErrInvalidOption = errors.New("dynamoql: Invalid option")
+)
+
+// ValidationError a QueryBuilder (or any other builder) validation failure.
+//
+// Use errors.Is to match the underlying error (e.g. ErrInvalidKeyCondition).
+type ValidationError struct {
+	// Field attribute name of the failing Condition, empty if the failure is not related to a single Condition.
+	Field string
+	// Reason description of the failure.
+	Reason string
+	// Err underlying error.
+	Err error
+}
+
+var _ error = ValidationError{}
+
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error() + ", " + e.Reason
+	}
+	return e.Err.Error() + " (" + e.Field + "), " + e.Reason
+}
+
+// Unwrap retrieves the underlying error.
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func newValidationError(err error, field, reason string) ValidationError {
+	return ValidationError{
+		Field:  fieldName(field),
+		Reason: reason,
+		Err:    err,
+	}
+}
+
+// Validate verifies the current QueryBuilder instance values are accepted by Amazon DynamoDB APIs, returning a
+// ValidationError if not.
+//
+// Rules specific to an API operation (e.g. DegreeOfParallelism is only available for Scan operations) are verified
+// by input builders (NewQueryInput, NewScanInput and NewGetInput), which call Validate as well.
+func (q *QueryBuilder) Validate() error {
+	if q.table == "" {
+		return newValidationError(ErrMissingTable, "", "table name is required (From)")
+	} else if q.limit <= 0 {
+		return newValidationError(ErrInvalidOption, "", "limit must be greater than zero")
+	} else if q.parallelDegree < 0 {
+		return newValidationError(ErrInvalidOption, "", "degree of parallelism must be positive")
+	} else if q.ordering != Ascend && q.ordering != Descend {
+		return newValidationError(ErrInvalidOption, "", "unknown ordering "+string(q.ordering))
+	} else if !isValidLogicalOperator(q.operator) {
+		return newValidationError(ErrInvalidLogicalOperator, "", "unknown logical operator "+string(q.operator))
+	}
+
+	keys, filters := 0, 0
+	for i := range q.conditions {
+		if q.conditions[i].IsKey {
+			keys++
+		} else {
+			filters++
+		}
+		if err := validateCondition(q.conditions[i], q.conditions[i].IsKey); err != nil {
+			return err
+		}
+	}
+	if q.operator == Or && keys > 0 && filters < 2 {
+		// key conditions are always concatenated with an And operator, hence Or only applies to filters
+		return newValidationError(ErrInvalidLogicalOperator, "",
+			"key conditions only accept And operator, use Or to concatenate two or more filter conditions")
+	}
+	return nil
+}
+
+// validateQuery verifies the current QueryBuilder instance values are accepted by Query API.
+func (q *QueryBuilder) validateQuery() error {
+	if err := q.Validate(); err != nil {
+		return err
+	} else if q.parallelDegree > 0 {
+		return newValidationError(ErrInvalidOption, "", "degree of parallelism is only available for Scan operations")
+	}
+	return validateKeyConditions(keyConditions(q.conditions))
+}
+
+// validateScan verifies the current QueryBuilder instance values are accepted by Scan API.
+func (q *QueryBuilder) validateScan() error {
+	if err := q.Validate(); err != nil {
+		return err
+	} else if q.parallelDegree > maxTotalSegments {
+		return newValidationError(ErrInvalidOption, "",
+			"degree of parallelism must not exceed "+strconv.Itoa(maxTotalSegments)+" segments")
+	}
+	return nil
+}
+
+// validateGet verifies the current QueryBuilder instance values are accepted by GetItem API.
+func (q *QueryBuilder) validateGet() error {
+	if err := q.Validate(); err != nil {
+		return err
+	} else if q.parallelDegree > 0 {
+		return newValidationError(ErrInvalidOption, "", "degree of parallelism is only available for Scan operations")
+	} else if q.index != nil {
+		return newValidationError(ErrInvalidOption, "", "GetItem operations do not accept indexes")
+	}
+	for i := range q.conditions {
+		if !q.conditions[i].IsKey {
+			return newValidationError(ErrInvalidCondition, q.conditions[i].Field,
+				"GetItem operations only accept key conditions")
+		} else if q.conditions[i].Group != nil || q.conditions[i].Operator != Equals {
+			return newValidationError(ErrInvalidKeyCondition, q.conditions[i].Field,
+				"GetItem operations only accept Equals key conditions")
+		}
+	}
+	return validateKeyConditions(q.conditions)
+}
+
+// validateWriteConditions verifies the given conditions form a valid condition expression of a write operation.
+func validateWriteConditions(op LogicalOperator, c []Condition) error {
+	if !isValidLogicalOperator(op) {
+		return newValidationError(ErrInvalidLogicalOperator, "", "unknown logical operator "+string(op))
+	}
+	for i := range c {
+		if err := validateCondition(c[i], false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateWriteReturnValues verifies the given return values are accepted by PutItem and DeleteItem APIs.
+func validateWriteReturnValues(v types.ReturnValue) error {
+	switch v {
+	case "", types.ReturnValueNone, types.ReturnValueAllOld:
+		return nil
+	default:
+		return newValidationError(ErrInvalidOption, "", "return values must be either NONE or ALL_OLD, got "+
+			string(v))
+	}
+}
+
+// keyConditions retrieves key conditions from c, flattening key groups as done by key expressions.
+func keyConditions(c []Condition) []Condition {
+	buf := make([]Condition, 0, len(c))
+	for i := range c {
+		if !c[i].IsKey {
+			continue
+		} else if c[i].Group == nil {
+			buf = append(buf, c[i])
+			continue
+		}
+		for _, nested := range flattenConditions(c[i].Group.Conditions) {
+			nested.IsKey = true
+			buf = append(buf, nested)
+		}
+	}
+	return buf
+}
+
+// validateKeyConditions verifies key conditions from c form a valid key expression (a partition key Equals
+// condition and an optional sort key condition).
+func validateKeyConditions(c []Condition) error {
+	keys := len(c)
+	hasEquals := false
+	for i := range c {
+		hasEquals = hasEquals || c[i].Operator == Equals
+	}
+	if keys == 0 {
+		return newValidationError(ErrMissingKeyCondition, "", "a partition key condition is required")
+	} else if keys > maxKeyConditions {
+		return newValidationError(ErrInvalidKeyCondition, "",
+			"only partition and sort key conditions are accepted, got "+strconv.Itoa(keys)+" key conditions")
+	} else if !hasEquals {
+		return newValidationError(ErrInvalidKeyCondition, "", "partition key condition requires Equals operator")
+	}
+	return nil
+}
+
+// validateCondition verifies the given Condition is well-formed. isKey indicates whether c belongs to a key
+// expression, as nested conditions of a key group are handled as key conditions.
+func validateCondition(c Condition, isKey bool) error {
+	if c.Group != nil {
+		return validateConditionGroup(c, isKey)
+	} else if c.Field == "" {
+		return newValidationError(ErrInvalidCondition, "", "field is required")
+	} else if c.IsKey && !isKey {
+		return newValidationError(ErrInvalidKeyCondition, c.Field,
+			"key conditions cannot be nested within filter groups")
+	}
+	if isKey {
+		if err := validateKeyCondition(c); err != nil {
+			return err
+		}
+	}
+
+	if err := validateValue(ErrInvalidCondition, c.Field, c.Value); err != nil {
+		return err
+	}
+	for i := range c.ExtraValues {
+		if err := validateValue(ErrInvalidCondition, c.Field, c.ExtraValues[i]); err != nil {
+			return err
+		}
+	}
+
+	op := c.Operator
+	if op == Size {
+		switch c.SecondaryOperator {
+		case Equals, GreaterThan, GreaterOrEqualThan, LessThan, LessOrEqualThan, GreaterOrLess, Between, In:
+		default:
+			return newValidationError(ErrInvalidCondition, c.Field,
+				"size operator requires a comparator, Between or In secondary operator")
+		}
+		op = c.SecondaryOperator
+	}
+	switch op {
+	case AttributeExists, AttributeNotExists:
+		return nil
+	case Between:
+		if c.Value == nil || len(c.ExtraValues) == 0 || c.ExtraValues[0] == nil {
+			return newValidationError(ErrInvalidCondition, c.Field,
+				"Between operator requires a Value (lower bound) and an ExtraValues upper bound")
+		}
+		return nil
+	case In:
+		if c.Value == nil {
+			return newValidationError(ErrInvalidCondition, c.Field, "In operator requires at least a Value")
+		} else if len(c.ExtraValues)+1 > maxInOperands {
+			return newValidationError(ErrInvalidCondition, c.Field,
+				"In operator accepts up to "+strconv.Itoa(maxInOperands)+" operands")
+		}
+		return nil
+	case Equals, GreaterThan, GreaterOrEqualThan, LessThan, LessOrEqualThan, GreaterOrLess, Contains, BeginsWith,
+		AttributeType:
+		if c.Value == nil {
+			return newValidationError(ErrInvalidCondition, c.Field, string(op)+" operator requires a Value")
+		}
+		return nil
+	default:
+		return newValidationError(ErrInvalidCondition, c.Field, "unknown operator "+string(op))
+	}
+}
+
+// validateKeyCondition verifies the given Condition is accepted by key expressions.
+//
+// See ref: https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/Query.html#Query.KeyConditionExpressions
+func validateKeyCondition(c Condition) error {
+	if c.Negate {
+		return newValidationError(ErrInvalidKeyCondition, c.Field, "key conditions cannot be negated")
+	}
+	switch c.Operator {
+	case Equals, GreaterThan, GreaterOrEqualThan, LessThan, LessOrEqualThan, Between, BeginsWith:
+		return nil
+	default:
+		return newValidationError(ErrInvalidKeyCondition, c.Field,
+			string(c.Operator)+" operator is not accepted by key conditions")
+	}
+}
+
+// validateConditionGroup verifies the given Condition holding a ConditionGroup and its nested conditions.
+func validateConditionGroup(c Condition, isKey bool) error {
+	if len(c.Group.Conditions) == 0 {
+		return newValidationError(ErrInvalidCondition, "", "groups require at least one condition")
+	} else if !isValidLogicalOperator(c.Group.Operator) {
+		return newValidationError(ErrInvalidLogicalOperator, "",
+			"unknown logical operator "+string(c.Group.Operator))
+	} else if isKey && c.Group.Operator == Or {
+		return newValidationError(ErrInvalidLogicalOperator, "", "key groups only accept And operator")
+	} else if isKey && c.Negate {
+		return newValidationError(ErrInvalidKeyCondition, "", "key groups cannot be negated")
+	}
+	for i := range c.Group.Conditions {
+		nested := c.Group.Conditions[i]
+		if isKey {
+			// key groups are flattened, hence nested conditions are handled as key conditions
+			nested.IsKey = true
+		}
+		if err := validateCondition(nested, isKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateValue verifies v can be converted into an Amazon DynamoDB attribute (see MarshalAttribute), returning a
+// ValidationError wrapping err if not. Both nil values and FieldRef operands are accepted.
+func validateValue(err error, field string, v interface{}) error {
+	if _, ok := v.(FieldRef); ok || v == nil {
+		return nil
+	} else if _, errAttr := MarshalAttribute(v); errAttr != nil {
+		return newValidationError(err, field, fmt.Sprintf("unsupported value type %T", v))
+	}
+	return nil
+}
+
+func isValidLogicalOperator(op LogicalOperator) bool {
+	return op == "" || op == And || op == Or
+}
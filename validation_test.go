@@ -0,0 +1,284 @@
+package dynamoql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/maestre3d/dynamoql-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	partitionKeyCondition = dynamoql.Condition{
+		IsKey:    true,
+		Operator: dynamoql.Equals,
+		Field:    "PK",
+		Value:    "foo",
+	}
+	sortKeyCondition = dynamoql.Condition{
+		IsKey:    true,
+		Operator: dynamoql.BeginsWith,
+		Field:    "SK",
+		Value:    "bar",
+	}
+)
+
+func TestQueryBuilder_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *dynamoql.QueryBuilder
+		exp   error
+	}{
+		{
+			name:  "Missing table",
+			query: dynamoql.Select().Where(partitionKeyCondition),
+			exp:   dynamoql.ErrMissingTable,
+		},
+		{
+			name:  "Invalid limit",
+			query: dynamoql.Select().From("sample").Limit(0),
+			exp:   dynamoql.ErrInvalidOption,
+		},
+		{
+			name:  "Negative parallelism",
+			query: dynamoql.Select().From("sample").DegreeOfParallelism(-1),
+			exp:   dynamoql.ErrInvalidOption,
+		},
+		{
+			name:  "Or key conditions",
+			query: dynamoql.Select().From("sample").Or().Where(partitionKeyCondition, sortKeyCondition),
+			exp:   dynamoql.ErrInvalidLogicalOperator,
+		},
+		{
+			name: "Or filter conditions",
+			query: dynamoql.Select().From("sample").Or().Where(partitionKeyCondition, dynamoql.Condition{
+				Operator: dynamoql.Equals,
+				Field:    "status",
+				Value:    "ACTIVE",
+			}, dynamoql.Condition{
+				Operator: dynamoql.AttributeExists,
+				Field:    "deleted_at",
+			}),
+		},
+		{
+			name: "Contains key condition",
+			query: dynamoql.Select().From("sample").Where(partitionKeyCondition, dynamoql.Condition{
+				IsKey:    true,
+				Operator: dynamoql.Contains,
+				Field:    "SK",
+				Value:    "bar",
+			}),
+			exp: dynamoql.ErrInvalidKeyCondition,
+		},
+		{
+			name: "Negated key condition",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Negate:   true,
+				IsKey:    true,
+				Operator: dynamoql.Equals,
+				Field:    "PK",
+				Value:    "foo",
+			}),
+			exp: dynamoql.ErrInvalidKeyCondition,
+		},
+		{
+			name:  "Or key group",
+			query: dynamoql.Select().From("sample").Where(keyGroup(dynamoql.Or)),
+			exp:   dynamoql.ErrInvalidLogicalOperator,
+		},
+		{
+			name:  "And key group",
+			query: dynamoql.Select().From("sample").Where(keyGroup(dynamoql.And)),
+		},
+		{
+			name: "Key condition within filter group",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Group(dynamoql.Or, partitionKeyCondition,
+				dynamoql.Condition{
+					Operator: dynamoql.Equals,
+					Field:    "status",
+					Value:    "ACTIVE",
+				})),
+			exp: dynamoql.ErrInvalidKeyCondition,
+		},
+		{
+			name:  "Empty group",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Group(dynamoql.Or)),
+			exp:   dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Missing field",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator: dynamoql.Equals,
+				Value:    "foo",
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Missing value",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator: dynamoql.Equals,
+				Field:    "status",
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Unsupported value",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator:    dynamoql.In,
+				Field:       "status",
+				Value:       "ACTIVE",
+				ExtraValues: []interface{}{make(chan int)},
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Unknown operator",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator: "LIKE",
+				Field:    "status",
+				Value:    "foo",
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Between without extra values",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator: dynamoql.Between,
+				Field:    "amount",
+				Value:    10,
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Between",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator:    dynamoql.Between,
+				Field:       "amount",
+				Value:       10,
+				ExtraValues: []interface{}{20},
+			}),
+		},
+		{
+			name: "In without values",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator: dynamoql.In,
+				Field:    "status",
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Size without secondary operator",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator: dynamoql.Size,
+				Field:    "tags",
+				Value:    10,
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Size between without extra values",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator:          dynamoql.Size,
+				SecondaryOperator: dynamoql.Between,
+				Field:             "tags",
+				Value:             10,
+			}),
+			exp: dynamoql.ErrInvalidCondition,
+		},
+		{
+			name: "Attribute exists",
+			query: dynamoql.Select().From("sample").Where(dynamoql.Condition{
+				Operator: dynamoql.AttributeExists,
+				Field:    "deleted_at",
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Validate()
+			assert.ErrorIs(t, err, tt.exp)
+			if tt.exp == nil {
+				return
+			}
+			var errValidation dynamoql.ValidationError
+			assert.True(t, errors.As(err, &errValidation))
+		})
+	}
+}
+
+func keyGroup(op dynamoql.LogicalOperator) dynamoql.Condition {
+	c := dynamoql.Group(op, partitionKeyCondition, sortKeyCondition)
+	c.IsKey = true
+	return c
+}
+
+func TestNewInputValidation(t *testing.T) {
+	filter := dynamoql.Condition{
+		Operator: dynamoql.Equals,
+		Field:    "status",
+		Value:    "ACTIVE",
+	}
+	tests := []struct {
+		name     string
+		query    *dynamoql.QueryBuilder
+		expQuery error
+		expScan  error
+		expGet   error
+	}{
+		{
+			name:     "Missing table",
+			query:    dynamoql.Select(),
+			expQuery: dynamoql.ErrMissingTable,
+			expScan:  dynamoql.ErrMissingTable,
+			expGet:   dynamoql.ErrMissingTable,
+		},
+		{
+			name:  "Keys",
+			query: dynamoql.Select().From("sample").Where(partitionKeyCondition, sortKeyCondition),
+			// GetItem only accepts Equals key conditions
+			expGet: dynamoql.ErrInvalidKeyCondition,
+		},
+		{
+			name:     "Filters",
+			query:    dynamoql.Select().From("sample").Where(filter),
+			expQuery: dynamoql.ErrMissingKeyCondition,
+			expGet:   dynamoql.ErrInvalidCondition,
+		},
+		{
+			name:     "Missing partition key",
+			query:    dynamoql.Select().From("sample").Where(sortKeyCondition),
+			expQuery: dynamoql.ErrInvalidKeyCondition,
+			expGet:   dynamoql.ErrInvalidKeyCondition,
+		},
+		{
+			name: "Too many keys",
+			query: dynamoql.Select().From("sample").
+				Where(partitionKeyCondition, partitionKeyCondition, partitionKeyCondition),
+			expQuery: dynamoql.ErrInvalidKeyCondition,
+			expGet:   dynamoql.ErrInvalidKeyCondition,
+		},
+		{
+			name:     "Parallelism",
+			query:    dynamoql.Select().From("sample").Where(partitionKeyCondition).DegreeOfParallelism(4),
+			expQuery: dynamoql.ErrInvalidOption,
+			expGet:   dynamoql.ErrInvalidOption,
+		},
+		{
+			name:     "Index",
+			query:    dynamoql.Select().From("sample").Where(partitionKeyCondition).Index("sample-gsi"),
+			expQuery: nil,
+			expScan:  nil,
+			expGet:   dynamoql.ErrInvalidOption,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dynamoql.NewQueryInput(tt.query)
+			assert.ErrorIs(t, err, tt.expQuery)
+			_, err = dynamoql.NewScanInput(tt.query)
+			assert.ErrorIs(t, err, tt.expScan)
+			_, err = dynamoql.NewGetInput(tt.query)
+			assert.ErrorIs(t, err, tt.expGet)
+		})
+	}
+}